@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/control"
+	"github-todoist-sync/internal/daemon"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/sync"
+)
+
+// runCtl ovládá běžící daemon přes jeho řídicí API a vrací návratový kód.
+func runCtl(args []string) int {
+	defaultAddr, language := config.LoadControl()
+	_ = i18n.SetLanguage(language)
+
+	flags := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, i18n.T(i18n.MsgCtlUsage)) }
+	addr := flags.String("addr", defaultAddr, i18n.T(i18n.MsgCtlAddrFlag))
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	var (
+		method = http.MethodPost
+		path   string
+	)
+	switch flags.Arg(0) {
+	case "status":
+		method, path = http.MethodGet, "/status"
+	case "sync":
+		path = "/sync"
+		if ref := flags.Arg(1); ref != "" {
+			if _, err := control.ParseIssueRef(ref); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			path += "?issue=" + url.QueryEscape(ref)
+		}
+	case "pause":
+		path = "/pause"
+	case "resume":
+		path = "/resume"
+	default:
+		flags.Usage()
+		return 1
+	}
+
+	status, err := callControl(method, *addr, path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(i18n.MsgCtlFailed, err))
+		return 1
+	}
+
+	switch flags.Arg(0) {
+	case "sync":
+		fmt.Println(i18n.T(i18n.MsgCtlSyncQueued))
+	case "pause":
+		fmt.Println(i18n.T(i18n.MsgCtlPaused))
+	case "resume":
+		fmt.Println(i18n.T(i18n.MsgCtlResumed))
+	}
+	printStatus(status)
+	return 0
+}
+
+func callControl(method, addr, path string) (*daemon.Status, error) {
+	req, err := http.NewRequest(method, "http://"+addr+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var status daemon.Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, i18n.Errorf(i18n.MsgCtlInvalidResponse, err)
+	}
+	return &status, nil
+}
+
+func printStatus(status *daemon.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	state := i18n.T(i18n.MsgStatusActive)
+	if status.Paused {
+		state = i18n.T(i18n.MsgStatusPaused)
+	}
+	fmt.Fprintf(w, "%s\t%s\n", i18n.T(i18n.MsgStatusSchedule), state)
+	if status.Running != "" {
+		fmt.Fprintf(w, "%s\t%s (%v)\n", i18n.T(i18n.MsgStatusRunning), status.Running, time.Since(status.RunningSince).Round(time.Second))
+	}
+	if p := status.Progress; p != nil {
+		phase := i18n.T(i18n.MsgPhaseToTodoist)
+		if p.Phase == sync.PhaseToGitHub {
+			phase = i18n.T(i18n.MsgPhaseToGitHub)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", i18n.T(i18n.MsgStatusProgress),
+			i18n.T(i18n.MsgStatusProgressLine, phase, p.ReposDone, p.ReposTotal, p.ActionsDone, p.ActionsPlanned))
+	}
+	fmt.Fprintf(w, "%s\t%d\n", i18n.T(i18n.MsgStatusQueued), status.Queued)
+	fmt.Fprintf(w, "%s\t%s\n", i18n.T(i18n.MsgStatusLastRun), formatTime(status.LastRun))
+	fmt.Fprintf(w, "%s\t%s\n", i18n.T(i18n.MsgStatusLastSuccess), formatTime(status.LastSuccess))
+	if status.LastResult != "" {
+		fmt.Fprintf(w, "%s\t%s\n", i18n.T(i18n.MsgStatusLastResult), status.LastResult)
+	}
+	for _, itemErr := range status.LastItemErrors {
+		fmt.Fprintf(w, "%s\t%s\n", i18n.T(i18n.MsgStatusItemError), itemErr)
+	}
+	if status.ConsecutiveFailures > 0 {
+		fmt.Fprintf(w, "%s\t%d (%s)\n", i18n.T(i18n.MsgStatusFailures), status.ConsecutiveFailures, status.LastError)
+	}
+	fmt.Fprintf(w, "%s\n", status.GitHubRateLimit)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -2,110 +2,301 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/control"
+	"github-todoist-sync/internal/daemon"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+	"github-todoist-sync/internal/metrics"
+	"github-todoist-sync/internal/redact"
 	"github-todoist-sync/internal/sync"
+	"github-todoist-sync/internal/webhook"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "ctl" {
+		os.Exit(runCtl(os.Args[2:]))
+	}
+
+	// Nápověda a chyby přepínačů se vypisují ještě před načtením
+	// konfigurace, jazyk proto bereme jen z prostředí.
+	_ = i18n.SetLanguage(config.LoadLanguage())
+
 	var (
-		mode    = flag.String("mode", "once", "Režim spuštění: 'once', 'daemon', 'github-only', 'todoist-only'")
-		verbose = flag.Bool("verbose", false, "Podrobné logování")
+		mode       = flag.String("mode", "once", i18n.T(i18n.MsgFlagMode))
+		verbose    = flag.Bool("verbose", false, i18n.T(i18n.MsgFlagVerbose))
+		configPath = flag.String("config", "", i18n.T(i18n.MsgFlagConfig))
+		dryRun     = flag.Bool("dry-run", false, i18n.T(i18n.MsgFlagDryRun))
+		output     = flag.String("output", "table", i18n.T(i18n.MsgFlagOutput))
 	)
 	flag.Parse()
 
-	if *verbose {
-		log.SetFlags(log.LstdFlags | log.Lshortfile)
+	if *dryRun && (*mode == "daemon" || *mode == "server") {
+		fmt.Fprint(os.Stderr, i18n.T(i18n.MsgUsageDryRunMode, *mode))
+		os.Exit(1)
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprint(os.Stderr, i18n.T(i18n.MsgUsageOutput, *output))
+		os.Exit(1)
 	}
 
 	// Načteme konfiguraci
-	cfg, err := config.Load()
+	var cfg *config.Config
+	var err error
+	if *configPath != "" {
+		cfg, err = config.LoadFile(*configPath)
+	} else {
+		cfg, err = config.Load()
+	}
+	if err != nil {
+		logging.Fatal(i18n.MsgConfigLoadFailed, logging.Err(err))
+	}
+	cfg.App.DryRun = *dryRun
+	redact.Add(cfg.GitHub.Token, cfg.Todoist.Token, cfg.Server.GitHubWebhookSecret, cfg.Server.TodoistClientSecret)
+
+	err = logging.Setup(os.Stderr, logging.Options{
+		Format:   cfg.Log.Format,
+		Language: cfg.Log.Language,
+		Debug:    cfg.App.Debug || *verbose,
+		Source:   *verbose,
+	})
 	if err != nil {
-		log.Fatalf("Chyba při načítání konfigurace: %v", err)
+		logging.Fatal(i18n.MsgConfigLoadFailed, logging.Err(err))
 	}
 
-	if cfg.App.Debug {
-		log.Printf("Debug režim zapnut")
-		log.Printf("GitHub: %s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
-		log.Printf("Todoist projekt: %s", cfg.Todoist.ProjectName)
-		log.Printf("Interval synchronizace: %v", cfg.App.SyncInterval)
+	logging.Debug(i18n.MsgDebugEnabled)
+	for _, pair := range cfg.Pairs {
+		logging.Debug(i18n.MsgConfigPair, logging.Repo(pair.FullName()), "project", pair.Project)
 	}
+	logging.Debug(i18n.MsgConfigInterval, "interval", cfg.App.SyncInterval)
+
+	ctx := context.Background()
 
 	// Vytvoříme synchronizační službu
-	syncService, err := sync.NewService(cfg)
+	syncService, err := sync.NewService(ctx, cfg)
 	if err != nil {
-		log.Fatalf("Chyba při inicializaci služby: %v", err)
+		logging.Fatal(i18n.MsgServiceInitFailed, logging.Err(err))
 	}
 
-	ctx := context.Background()
-
+	var run func(context.Context) (*sync.SyncResult, error)
 	switch *mode {
 	case "once":
-		log.Printf("Spouštím jednorazovou synchronizaci...")
-		if err := syncService.FullSync(ctx); err != nil {
-			log.Fatalf("Chyba při synchronizaci: %v", err)
-		}
-		log.Printf("Synchronizace dokončena")
+		logging.Info(i18n.MsgRunOnce)
+		run = syncService.FullSync
 
 	case "github-only":
-		log.Printf("Spouštím synchronizaci pouze GitHub → Todoist...")
-		if err := syncService.SyncFromGitHub(ctx); err != nil {
-			log.Fatalf("Chyba při synchronizaci z GitHub: %v", err)
-		}
-		log.Printf("Synchronizace z GitHub dokončena")
+		logging.Info(i18n.MsgRunGitHubOnly)
+		run = syncService.SyncFromGitHub
 
 	case "todoist-only":
-		log.Printf("Spouštím synchronizaci pouze Todoist → GitHub...")
-		if err := syncService.SyncToGitHub(ctx); err != nil {
-			log.Fatalf("Chyba při synchronizaci do GitHub: %v", err)
-		}
-		log.Printf("Synchronizace do GitHub dokončena")
+		logging.Info(i18n.MsgRunTodoistOnly)
+		run = syncService.SyncToGitHub
 
 	case "daemon":
-		log.Printf("Spouštím službu v daemon režimu (interval: %v)...", cfg.App.SyncInterval)
-		runDaemon(ctx, syncService, cfg.App.SyncInterval)
+		d := daemon.New(syncService, cfg.App.SyncInterval)
+		var server *http.Server
+		if cfg.Server.Health || cfg.Server.Metrics {
+			server, _ = newServer(cfg.Server, d, false)
+		}
+		logging.Info(i18n.MsgRunDaemon, "interval", cfg.App.SyncInterval)
+		runDaemon(ctx, d, httpServer{server: server}, httpServer{server: newControlServer(cfg.Server, d)})
+
+	case "server":
+		d := daemon.New(syncService, cfg.App.SyncInterval)
+		server, err := newServer(cfg.Server, d, true)
+		if err != nil {
+			logging.Fatal(i18n.MsgServerSetupFailed, logging.Err(err))
+		}
+		logging.Info(i18n.MsgRunServer, "addr", cfg.Server.Addr, "interval", cfg.App.SyncInterval)
+		runDaemon(ctx, d, httpServer{server: server, required: true}, httpServer{server: newControlServer(cfg.Server, d)})
 
 	default:
-		fmt.Fprintf(os.Stderr, "Neplatný režim: %s\nPovolené režimy: once, daemon, github-only, todoist-only\n", *mode)
+		fmt.Fprint(os.Stderr, i18n.T(i18n.MsgUsageMode, *mode))
 		os.Exit(1)
 	}
+
+	if run == nil {
+		return
+	}
+
+	result, err := run(ctx)
+	if *dryRun {
+		printPlan(syncService.Plan(), *output)
+	}
+	printSummary(result)
+	if err != nil {
+		logging.Fatal(i18n.MsgSyncFailed, logging.Err(err))
+	}
+	if result.Failed() {
+		// Odlišný návratový kód od fatální chyby, aby cron a CI poznaly
+		// částečné selhání.
+		logging.Warn(i18n.MsgRunDoneWithErrors)
+		os.Exit(2)
+	}
+	logging.Info(i18n.MsgRunDone)
+}
+
+// printSummary vypíše výsledek synchronizace. Chyby jednotlivých položek
+// vypíše všechny, aby je bylo vidět i ve výstupu cronu nebo CI.
+func printSummary(result *sync.SyncResult) {
+	if result == nil {
+		return
+	}
+
+	logging.Info(i18n.MsgRunResult, "result", result)
+	for _, skipped := range result.Skipped {
+		logging.Info(i18n.MsgRunSkipped, "item", skipped)
+	}
+	for _, itemErr := range result.Errors {
+		logging.Error(i18n.MsgRunItemFailed, "item", itemErr.Item, logging.Action(string(itemErr.Action)), logging.Err(itemErr.Err))
+	}
+}
+
+func printPlan(changes []sync.Action, format string) {
+	if format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if changes == nil {
+			changes = []sync.Action{}
+		}
+		_ = encoder.Encode(changes)
+		return
+	}
+
+	if len(changes) == 0 {
+		fmt.Println(i18n.T(i18n.MsgPlanEmpty))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, i18n.T(i18n.MsgPlanHeader))
+	for _, change := range changes {
+		issue := ""
+		if change.Issue != 0 {
+			issue = fmt.Sprintf("#%d", change.Issue)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", change.Type, change.Repo, issue, change.TaskID, change.Detail)
+	}
+	w.Flush()
+	fmt.Printf("\n%s\n", i18n.T(i18n.MsgPlanTotal, len(changes)))
 }
 
-func runDaemon(ctx context.Context, syncService *sync.Service, interval time.Duration) {
+// newServer sestaví HTTP server s health a readiness endpointy, případně
+// s metrikami. S webhooks přijímá i webhooky (režim server).
+func newServer(cfg config.ServerConfig, d *daemon.Daemon, webhooks bool) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", d.HealthHandler(cfg.HealthMaxFailures, cfg.HealthMaxAge))
+	mux.Handle("/readyz", d.ReadyHandler())
+
+	if webhooks {
+		if cfg.GitHubWebhookSecret == "" && cfg.TodoistClientSecret == "" {
+			return nil, i18n.Errorf(i18n.MsgErrServerSecrets)
+		}
+		if cfg.GitHubWebhookSecret != "" {
+			logging.Info(i18n.MsgWebhooksEnabled, "source", "github", "path", "/webhooks/github")
+			mux.Handle("/webhooks/github", webhook.NewGitHubHandler(cfg.GitHubWebhookSecret, d))
+		}
+		if cfg.TodoistClientSecret != "" {
+			logging.Info(i18n.MsgWebhooksEnabled, "source", "todoist", "path", "/webhooks/todoist")
+			mux.Handle("/webhooks/todoist", webhook.NewTodoistHandler(cfg.TodoistClientSecret, d))
+		}
+	}
+	if cfg.Metrics {
+		logging.Info(i18n.MsgMetricsEnabled, "addr", cfg.Addr, "path", "/metrics")
+		mux.Handle("/metrics", metrics.Handler())
+	}
+
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}, nil
+}
+
+// newControlServer sestaví server řídicího API, pokud není vypnuté.
+func newControlServer(cfg config.ServerConfig, d *daemon.Daemon) *http.Server {
+	if !cfg.ControlEnabled() {
+		return nil
+	}
+	logging.Info(i18n.MsgControlListening, "addr", cfg.ControlAddr)
+	return &http.Server{
+		Addr:              cfg.ControlAddr,
+		Handler:           control.NewHandler(d),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// shutdownTimeout je doba, po kterou se při ukončení čeká na dokončení
+// rozběhnuté synchronizace a HTTP požadavků.
+const shutdownTimeout = 30 * time.Second
+
+// httpServer je HTTP server spuštěný vedle daemonu. Když se nepodaří
+// spustit server s required (webhooky), daemon skončí. Selhání ostatních
+// (health, metriky, řídicí API) se jen zaloguje a synchronizace běží dál.
+type httpServer struct {
+	server   *http.Server
+	required bool
+}
+
+func runDaemon(ctx context.Context, d *daemon.Daemon, servers ...httpServer) {
 	// Nastavíme zachytávání signálů pro graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Vytvoříme ticker pro pravidelnou synchronizaci
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	log.Printf("Daemon spuštěn. Pro ukončení stiskněte Ctrl+C")
+	go func() {
+		sig := <-sigChan
+		logging.Info(i18n.MsgSignalReceived, "signal", sig.String())
+		cancel()
+	}()
 
-	// Provedeme první synchronizaci ihned
-	log.Printf("Provádím počáteční synchronizaci...")
-	if err := syncService.FullSync(ctx); err != nil {
-		log.Printf("Chyba při počáteční synchronizaci: %v", err)
+	for _, s := range servers {
+		if s.server == nil {
+			continue
+		}
+		server, required := s.server, s.required
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logging.Error(i18n.MsgServerFailed, "addr", server.Addr, logging.Err(err))
+				if required {
+					cancel()
+				}
+			}
+		}()
+		defer func() {
+			shutdownCtx, done := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer done()
+			_ = server.Shutdown(shutdownCtx)
+		}()
 	}
 
-	for {
-		select {
-		case <-ticker.C:
-			log.Printf("Spouštím plánovanou synchronizaci...")
-			if err := syncService.FullSync(ctx); err != nil {
-				log.Printf("Chyba při synchronizaci: %v", err)
-			}
+	logging.Info(i18n.MsgDaemonStarted)
+	stopped := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(stopped)
+	}()
 
-		case sig := <-sigChan:
-			log.Printf("Přijat signál %v, ukončuji...", sig)
-			return
-		}
+	// Zrušený kontext přeruší rozběhnuté požadavky, synchronizace se tak
+	// ukončí rychle. Na dokončení čekáme nejvýše shutdownTimeout.
+	<-ctx.Done()
+	select {
+	case <-stopped:
+		logging.Info(i18n.MsgDaemonStopped)
+	case <-time.After(shutdownTimeout):
+		logging.Warn(i18n.MsgDaemonStopTimeout, "timeout", shutdownTimeout)
 	}
 }
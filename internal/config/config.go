@@ -1,11 +1,13 @@
 package config
 
 import (
-	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"github-todoist-sync/internal/i18n"
+
 	"github.com/joho/godotenv"
 )
 
@@ -13,6 +15,15 @@ type Config struct {
 	GitHub  GitHubConfig
 	Todoist TodoistConfig
 	App     AppConfig
+	Server  ServerConfig
+	Log     LogConfig
+	Pairs   []SyncPair
+}
+
+// LogConfig nastavuje formát logu ("text" nebo "json") a jazyk zpráv.
+type LogConfig struct {
+	Format   string
+	Language string
 }
 
 type GitHubConfig struct {
@@ -22,13 +33,180 @@ type GitHubConfig struct {
 }
 
 type TodoistConfig struct {
-	Token       string
-	ProjectName string
+	Token          string
+	ProjectName    string
+	SectionPerRepo bool
+	Retry          RetryConfig
+}
+
+// RetryConfig nastavuje opakování Todoist požadavků při limitu (429) nebo
+// dočasné chybě serveru.
+type RetryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+func defaultRetry() RetryConfig {
+	return RetryConfig{
+		MaxRetries: getEnvInt("TODOIST_MAX_RETRIES", 3),
+		BaseDelay:  getEnvDuration("TODOIST_RETRY_BASE_DELAY", time.Second),
+		MaxDelay:   getEnvDuration("TODOIST_RETRY_MAX_DELAY", 30*time.Second),
+	}
+}
+
+// SyncPair propojuje jeden GitHub repozitář s Todoist projektem, případně
+// se sekcí ve sdíleném projektu.
+type SyncPair struct {
+	Owner      string
+	Repo       string
+	Project    string
+	Section    string
+	Filters    Filters
+	Priorities PriorityRules
+	Directions Directions
+	Conflicts  ConflictPolicy
+}
+
+func (p SyncPair) FullName() string {
+	return p.Owner + "/" + p.Repo
+}
+
+// Filters omezují, pro která issues se zakládají nové úkoly. Již propojená
+// issues se synchronizují dál, i když filtru přestanou odpovídat.
+type Filters struct {
+	Labels        []string
+	ExcludeLabels []string
+	State         string
+}
+
+func (f Filters) Match(state string, labels []string) bool {
+	if f.State == "open" && state != "open" {
+		return false
+	}
+	if len(f.Labels) > 0 && !containsAny(labels, f.Labels) {
+		return false
+	}
+	return !containsAny(labels, f.ExcludeLabels)
+}
+
+type Direction string
+
+const (
+	DirectionBoth      Direction = "both"
+	DirectionToTodoist Direction = "github-to-todoist"
+	DirectionToGitHub  Direction = "todoist-to-github"
+	DirectionNone      Direction = "none"
+)
+
+func (d Direction) ToTodoist() bool {
+	return d == DirectionBoth || d == DirectionToTodoist
+}
+
+func (d Direction) ToGitHub() bool {
+	return d == DirectionBoth || d == DirectionToGitHub
+}
+
+func (d Direction) valid() bool {
+	switch d {
+	case DirectionBoth, DirectionToTodoist, DirectionToGitHub, DirectionNone:
+		return true
+	}
+	return false
+}
+
+// Directions určují směr synchronizace pro jednotlivá pole.
+type Directions struct {
+	State    Direction
+	Title    Direction
+	Priority Direction
+}
+
+// ConflictPolicy určuje, která strana vyhraje, pokud se stejné pole od
+// poslední synchronizace změnilo na GitHubu i v Todoist.
+type ConflictPolicy string
+
+const (
+	ConflictLastWriterWins ConflictPolicy = "last-writer-wins"
+	ConflictGitHubWins     ConflictPolicy = "github-wins"
+	ConflictTodoistWins    ConflictPolicy = "todoist-wins"
+	ConflictManual         ConflictPolicy = "manual"
+)
+
+func (c ConflictPolicy) valid() bool {
+	switch c {
+	case ConflictLastWriterWins, ConflictGitHubWins, ConflictTodoistWins, ConflictManual:
+		return true
+	}
+	return false
+}
+
+// defaultDirections vrací směry polí, které platí, pokud je dvojice
+// nenastavuje. Proměnné STATE_DIRECTION, TITLE_DIRECTION a
+// PRIORITY_DIRECTION je mění pro všechny dvojice. Priorita se do GitHubu
+// bez nich nepromítá.
+func defaultDirections() Directions {
+	return Directions{
+		State:    Direction(getEnvOrDefault("STATE_DIRECTION", string(DirectionBoth))),
+		Title:    Direction(getEnvOrDefault("TITLE_DIRECTION", string(DirectionToTodoist))),
+		Priority: Direction(getEnvOrDefault("PRIORITY_DIRECTION", string(DirectionToTodoist))),
+	}
+}
+
+// ServerConfig nastavuje HTTP server režimu server. Webhook endpoint je
+// aktivní, jen pokud je nastaven jeho sdílený klíč.
+type ServerConfig struct {
+	Addr                string
+	GitHubWebhookSecret string
+	TodoistClientSecret string
+	// Metrics zpřístupní Prometheus metriky na /metrics, v režimu daemon
+	// i bez webhooků.
+	Metrics bool
+	// Health zpřístupní /healthz a /readyz i v režimu daemon. Režim server
+	// je má vždy.
+	Health bool
+	// HealthMaxFailures a HealthMaxAge určují, kdy /healthz hlásí
+	// degradovaný stav: po tolika neúspěšných synchronizacích za sebou
+	// (0 kontrolu vypne) nebo když je poslední úspěšná starší (výchozí je
+	// trojnásobek intervalu synchronizace).
+	HealthMaxFailures int
+	HealthMaxAge      time.Duration
+	// ControlAddr je adresa řídicího API daemonu. API nemá autentizaci,
+	// proto má naslouchat jen lokálně; hodnota "off" ho vypne.
+	ControlAddr string
+}
+
+// ControlEnabled říká, zda se má spustit řídicí API.
+func (c ServerConfig) ControlEnabled() bool {
+	return c.ControlAddr != "" && c.ControlAddr != "off"
+}
+
+const defaultControlAddr = "127.0.0.1:8081"
+
+// LoadControl vrací adresu řídicího API a jazyk zpráv z prostředí pro
+// příkaz ctl, který nepotřebuje zbytek konfigurace.
+func LoadControl() (addr, language string) {
+	return getEnvOrDefault("CONTROL_ADDR", defaultControlAddr), LoadLanguage()
+}
+
+// LoadLanguage vrací jazyk zpráv z prostředí. Příkazová řádka ho potřebuje
+// pro nápovědu ještě před načtením konfigurace.
+func LoadLanguage() string {
+	_ = godotenv.Load()
+	return getEnvOrDefault("LOG_LANGUAGE", i18n.DefaultLanguage)
 }
 
 type AppConfig struct {
 	SyncInterval time.Duration
-	Debug        bool
+	// FullRescanInterval určuje, jak často se místo změněných issues načtou
+	// všechna, aby se odhalila smazaná a přesunutá issues.
+	FullRescanInterval time.Duration
+	// Concurrency omezuje počet souběžně zpracovávaných issues.
+	Concurrency int
+	Debug       bool
+	StateFile   string
+	// DryRun jen vypočítá plánované změny a neprovede žádný zápis.
+	DryRun bool
 }
 
 func Load() (*Config, error) {
@@ -41,14 +219,42 @@ func Load() (*Config, error) {
 			Repo:  os.Getenv("GITHUB_REPO"),
 		},
 		Todoist: TodoistConfig{
-			Token:       os.Getenv("TODOIST_TOKEN"),
-			ProjectName: getEnvOrDefault("TODOIST_PROJECT_NAME", "GitHub Sync"),
+			Token:          os.Getenv("TODOIST_TOKEN"),
+			ProjectName:    getEnvOrDefault("TODOIST_PROJECT_NAME", "GitHub Sync"),
+			SectionPerRepo: getEnvBool("TODOIST_SECTION_PER_REPO", false),
+			Retry:          defaultRetry(),
 		},
 		App: AppConfig{
-			SyncInterval: getSyncInterval(),
-			Debug:        getEnvBool("DEBUG", false),
+			SyncInterval:       getSyncInterval(),
+			FullRescanInterval: getEnvDuration("FULL_RESCAN_INTERVAL", 24*time.Hour),
+			Concurrency:        getEnvInt("SYNC_CONCURRENCY", 4),
+			Debug:              getEnvBool("DEBUG", false),
+			StateFile:          getEnvOrDefault("STATE_FILE", "sync-state.json"),
+		},
+		Server: ServerConfig{
+			Addr:                getEnvOrDefault("SERVER_ADDR", ":8080"),
+			GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+			TodoistClientSecret: os.Getenv("TODOIST_CLIENT_SECRET"),
+			Metrics:             getEnvBool("METRICS_ENABLED", false),
+			Health:              getEnvBool("HEALTH_ENABLED", false),
+			HealthMaxFailures:   getEnvInt("HEALTH_MAX_FAILURES", 3),
+			HealthMaxAge:        getEnvDuration("HEALTH_MAX_AGE", 0),
+			ControlAddr:         getEnvOrDefault("CONTROL_ADDR", defaultControlAddr),
+		},
+		Log: LogConfig{
+			Format:   getEnvOrDefault("LOG_FORMAT", "text"),
+			Language: getEnvOrDefault("LOG_LANGUAGE", i18n.DefaultLanguage),
 		},
 	}
+	if config.Server.HealthMaxAge <= 0 {
+		config.Server.HealthMaxAge = 3 * config.App.SyncInterval
+	}
+
+	pairs, err := parsePairs(os.Getenv("GITHUB_REPOS"), config.GitHub, config.Todoist)
+	if err != nil {
+		return nil, err
+	}
+	config.Pairs = pairs
 
 	if err := config.validate(); err != nil {
 		return nil, err
@@ -59,20 +265,138 @@ func Load() (*Config, error) {
 
 func (c *Config) validate() error {
 	if c.GitHub.Token == "" {
-		return fmt.Errorf("GITHUB_TOKEN je povinný")
-	}
-	if c.GitHub.Owner == "" {
-		return fmt.Errorf("GITHUB_OWNER je povinný")
+		return i18n.Errorf(i18n.MsgErrGitHubToken)
 	}
-	if c.GitHub.Repo == "" {
-		return fmt.Errorf("GITHUB_REPO je povinný")
+	if len(c.Pairs) == 0 {
+		return i18n.Errorf(i18n.MsgErrRepos)
 	}
 	if c.Todoist.Token == "" {
-		return fmt.Errorf("TODOIST_TOKEN je povinný")
+		return i18n.Errorf(i18n.MsgErrTodoistToken)
+	}
+	if c.App.Concurrency < 1 {
+		return i18n.Errorf(i18n.MsgErrConcurrency)
+	}
+	if c.Todoist.Retry.MaxRetries < 0 {
+		return i18n.Errorf(i18n.MsgErrRetryCount)
+	}
+	if c.Todoist.Retry.BaseDelay > c.Todoist.Retry.MaxDelay {
+		return i18n.Errorf(i18n.MsgErrRetryWait)
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		return i18n.Errorf(i18n.MsgErrLogFormat, c.Log.Format)
+	}
+	if !i18n.Supported(c.Log.Language) {
+		return i18n.Errorf(i18n.MsgErrLanguage, c.Log.Language, strings.Join(i18n.Languages(), ", "))
+	}
+
+	seen := make(map[string]bool)
+	for _, pair := range c.Pairs {
+		if pair.Owner == "" || pair.Repo == "" {
+			return i18n.Errorf(i18n.MsgErrRepoFormat, pair.FullName())
+		}
+		name := strings.ToLower(pair.FullName())
+		if seen[name] {
+			return i18n.Errorf(i18n.MsgErrRepoDuplicate, pair.FullName())
+		}
+		seen[name] = true
+
+		if err := pair.validate(); err != nil {
+			return i18n.Errorf(i18n.MsgErrRepo, pair.FullName(), err)
+		}
+	}
+	return nil
+}
+
+func (p SyncPair) validate() error {
+	if p.Project == "" {
+		return i18n.Errorf(i18n.MsgErrProjectMissing)
+	}
+	for field, direction := range map[string]Direction{
+		"state":    p.Directions.State,
+		"title":    p.Directions.Title,
+		"priority": p.Directions.Priority,
+	} {
+		if !direction.valid() {
+			return i18n.Errorf(i18n.MsgErrDirection, direction, field)
+		}
+	}
+	if err := p.Priorities.validate(); err != nil {
+		return err
+	}
+	if !p.Conflicts.valid() {
+		return i18n.Errorf(i18n.MsgErrConflictPolicy, p.Conflicts)
+	}
+	if p.Filters.State != "" && p.Filters.State != "all" && p.Filters.State != "open" {
+		return i18n.Errorf(i18n.MsgErrStateFilter, p.Filters.State)
 	}
 	return nil
 }
 
+// parsePairs čte seznam repozitářů ve tvaru "owner/repo[=projekt]"
+// oddělených čárkou. Bez GITHUB_REPOS se použije jediná dvojice
+// z GITHUB_OWNER a GITHUB_REPO.
+func parsePairs(value string, github GitHubConfig, todoist TodoistConfig) ([]SyncPair, error) {
+	priorities := DefaultPriorityRules()
+	if value := os.Getenv("PRIORITY_RULES"); value != "" {
+		rules, err := parsePriorityRules(value)
+		if err != nil {
+			return nil, err
+		}
+		priorities = rules
+	}
+
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		if github.Owner == "" || github.Repo == "" {
+			return nil, nil
+		}
+		entries = []string{github.Owner + "/" + github.Repo}
+	}
+
+	var pairs []SyncPair
+	for _, entry := range entries {
+		repo, project, _ := strings.Cut(entry, "=")
+		owner, name, ok := strings.Cut(strings.TrimSpace(repo), "/")
+		if !ok {
+			return nil, i18n.Errorf(i18n.MsgErrRepoInvalid, entry)
+		}
+
+		pair := SyncPair{
+			Owner:      strings.TrimSpace(owner),
+			Repo:       strings.TrimSpace(name),
+			Project:    strings.TrimSpace(project),
+			Priorities: priorities,
+			Directions: defaultDirections(),
+			Conflicts:  ConflictPolicy(getEnvOrDefault("CONFLICT_POLICY", string(ConflictGitHubWins))),
+		}
+		if pair.Project == "" {
+			pair.Project = todoist.ProjectName
+		}
+		if todoist.SectionPerRepo {
+			pair.Section = pair.FullName()
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, nil
+}
+
+func containsAny(labels, wanted []string) bool {
+	for _, label := range labels {
+		for _, w := range wanted {
+			if strings.EqualFold(label, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -89,6 +413,24 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func getSyncInterval() time.Duration {
 	intervalStr := getEnvOrDefault("SYNC_INTERVAL_MINUTES", "15")
 	if minutes, err := strconv.Atoi(intervalStr); err == nil {
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type fileConfig struct {
+	GitHub struct {
+		Token string `yaml:"token"`
+	} `yaml:"github"`
+	Todoist struct {
+		Token          string `yaml:"token"`
+		Project        string `yaml:"project"`
+		SectionPerRepo bool   `yaml:"section_per_repo"`
+		Retry          struct {
+			MaxRetries *int          `yaml:"max_retries"`
+			BaseDelay  time.Duration `yaml:"base_delay"`
+			MaxDelay   time.Duration `yaml:"max_delay"`
+		} `yaml:"retry"`
+	} `yaml:"todoist"`
+	App struct {
+		SyncInterval       time.Duration  `yaml:"sync_interval"`
+		FullRescanInterval time.Duration  `yaml:"full_rescan_interval"`
+		Concurrency        int            `yaml:"concurrency"`
+		Debug              bool           `yaml:"debug"`
+		StateFile          string         `yaml:"state_file"`
+		ConflictPolicy     ConflictPolicy `yaml:"conflict_policy"`
+	} `yaml:"app"`
+	Server struct {
+		Addr                string `yaml:"addr"`
+		GitHubWebhookSecret string `yaml:"github_webhook_secret"`
+		TodoistClientSecret string `yaml:"todoist_client_secret"`
+		Metrics             bool   `yaml:"metrics"`
+		Health              struct {
+			Enabled     bool          `yaml:"enabled"`
+			MaxFailures *int          `yaml:"max_failures"`
+			MaxAge      time.Duration `yaml:"max_age"`
+		} `yaml:"health"`
+		ControlAddr string `yaml:"control_addr"`
+	} `yaml:"server"`
+	Log struct {
+		Format   string `yaml:"format"`
+		Language string `yaml:"language"`
+	} `yaml:"log"`
+	Pairs []filePair `yaml:"pairs"`
+}
+
+type filePair struct {
+	Repo    string `yaml:"repo"`
+	Project string `yaml:"project"`
+	Section string `yaml:"section"`
+	Filters struct {
+		Labels        []string `yaml:"labels"`
+		ExcludeLabels []string `yaml:"exclude_labels"`
+		State         string   `yaml:"state"`
+	} `yaml:"filters"`
+	PriorityRules []struct {
+		Pattern  string `yaml:"pattern"`
+		Priority int    `yaml:"priority"`
+		Label    string `yaml:"label"`
+	} `yaml:"priority_rules"`
+	// Priorities je starší zápis pravidel bez určeného pořadí.
+	Priorities     map[string]int `yaml:"priorities"`
+	ConflictPolicy ConflictPolicy `yaml:"conflict_policy"`
+	Direction      struct {
+		State    Direction `yaml:"state"`
+		Title    Direction `yaml:"title"`
+		Priority Direction `yaml:"priority"`
+	} `yaml:"direction"`
+}
+
+// LoadFile načte konfiguraci ze YAML souboru se seznamem synchronizačních
+// dvojic. Tokeny z proměnných prostředí mají přednost před souborem, aby
+// tajné údaje nemusely být uloženy v konfiguraci.
+func LoadFile(path string) (*Config, error) {
+	_ = godotenv.Load()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrConfigRead, err)
+	}
+
+	var file fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrConfigParse, path, err)
+	}
+
+	config := &Config{
+		GitHub: GitHubConfig{
+			Token: getEnvOrDefault("GITHUB_TOKEN", file.GitHub.Token),
+		},
+		Todoist: TodoistConfig{
+			Token:          getEnvOrDefault("TODOIST_TOKEN", file.Todoist.Token),
+			ProjectName:    file.Todoist.Project,
+			SectionPerRepo: file.Todoist.SectionPerRepo,
+			Retry:          defaultRetry(),
+		},
+		App: AppConfig{
+			SyncInterval:       file.App.SyncInterval,
+			FullRescanInterval: file.App.FullRescanInterval,
+			Concurrency:        file.App.Concurrency,
+			Debug:              file.App.Debug || getEnvBool("DEBUG", false),
+			StateFile:          file.App.StateFile,
+		},
+		Server: ServerConfig{
+			Addr:                file.Server.Addr,
+			GitHubWebhookSecret: getEnvOrDefault("GITHUB_WEBHOOK_SECRET", file.Server.GitHubWebhookSecret),
+			TodoistClientSecret: getEnvOrDefault("TODOIST_CLIENT_SECRET", file.Server.TodoistClientSecret),
+			Metrics:             file.Server.Metrics || getEnvBool("METRICS_ENABLED", false),
+			Health:              file.Server.Health.Enabled || getEnvBool("HEALTH_ENABLED", false),
+			HealthMaxFailures:   getEnvInt("HEALTH_MAX_FAILURES", 3),
+			HealthMaxAge:        file.Server.Health.MaxAge,
+			ControlAddr:         file.Server.ControlAddr,
+		},
+		Log: LogConfig{
+			Format:   file.Log.Format,
+			Language: file.Log.Language,
+		},
+	}
+
+	if config.Todoist.ProjectName == "" {
+		config.Todoist.ProjectName = "GitHub Sync"
+	}
+	if config.App.SyncInterval <= 0 {
+		config.App.SyncInterval = getSyncInterval()
+	}
+	if config.App.FullRescanInterval <= 0 {
+		config.App.FullRescanInterval = getEnvDuration("FULL_RESCAN_INTERVAL", 24*time.Hour)
+	}
+	if config.App.Concurrency <= 0 {
+		config.App.Concurrency = getEnvInt("SYNC_CONCURRENCY", 4)
+	}
+	if config.App.StateFile == "" {
+		config.App.StateFile = getEnvOrDefault("STATE_FILE", "sync-state.json")
+	}
+	if retry := file.Todoist.Retry; retry.MaxRetries != nil {
+		config.Todoist.Retry.MaxRetries = *retry.MaxRetries
+	}
+	if retry := file.Todoist.Retry; retry.BaseDelay > 0 {
+		config.Todoist.Retry.BaseDelay = retry.BaseDelay
+	}
+	if retry := file.Todoist.Retry; retry.MaxDelay > 0 {
+		config.Todoist.Retry.MaxDelay = retry.MaxDelay
+	}
+	if health := file.Server.Health; health.MaxFailures != nil {
+		config.Server.HealthMaxFailures = *health.MaxFailures
+	}
+	if config.Server.HealthMaxAge <= 0 {
+		config.Server.HealthMaxAge = getEnvDuration("HEALTH_MAX_AGE", 3*config.App.SyncInterval)
+	}
+	if config.Server.Addr == "" {
+		config.Server.Addr = getEnvOrDefault("SERVER_ADDR", ":8080")
+	}
+	if config.Server.ControlAddr == "" {
+		config.Server.ControlAddr = getEnvOrDefault("CONTROL_ADDR", defaultControlAddr)
+	}
+	if config.Log.Format == "" {
+		config.Log.Format = getEnvOrDefault("LOG_FORMAT", "text")
+	}
+	if config.Log.Language == "" {
+		config.Log.Language = getEnvOrDefault("LOG_LANGUAGE", i18n.DefaultLanguage)
+	}
+
+	conflicts := file.App.ConflictPolicy
+	if conflicts == "" {
+		conflicts = ConflictPolicy(getEnvOrDefault("CONFLICT_POLICY", string(ConflictGitHubWins)))
+	}
+
+	for _, fp := range file.Pairs {
+		pair, err := fp.toSyncPair(config.Todoist, conflicts)
+		if err != nil {
+			return nil, err
+		}
+		config.Pairs = append(config.Pairs, pair)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (fp filePair) toSyncPair(todoist TodoistConfig, conflicts ConflictPolicy) (SyncPair, error) {
+	owner, repo, ok := strings.Cut(fp.Repo, "/")
+	if !ok {
+		return SyncPair{}, i18n.Errorf(i18n.MsgErrRepoInvalid, fp.Repo)
+	}
+
+	pair := SyncPair{
+		Owner:   owner,
+		Repo:    repo,
+		Project: fp.Project,
+		Section: fp.Section,
+		Filters: Filters{
+			Labels:        fp.Filters.Labels,
+			ExcludeLabels: fp.Filters.ExcludeLabels,
+			State:         fp.Filters.State,
+		},
+		Priorities: DefaultPriorityRules(),
+		Directions: defaultDirections(),
+		Conflicts:  conflicts,
+	}
+	if fp.ConflictPolicy != "" {
+		pair.Conflicts = fp.ConflictPolicy
+	}
+
+	if pair.Project == "" {
+		pair.Project = todoist.ProjectName
+	}
+	if pair.Section == "" && todoist.SectionPerRepo {
+		pair.Section = pair.FullName()
+	}
+
+	switch {
+	case len(fp.PriorityRules) > 0 && len(fp.Priorities) > 0:
+		return SyncPair{}, i18n.Errorf(i18n.MsgErrPriorityForms, fp.Repo)
+	case len(fp.PriorityRules) > 0:
+		pair.Priorities = nil
+		for _, r := range fp.PriorityRules {
+			rule, err := NewPriorityRule(r.Pattern, r.Priority, r.Label)
+			if err != nil {
+				return SyncPair{}, i18n.Errorf(i18n.MsgErrRepo, fp.Repo, err)
+			}
+			pair.Priorities = append(pair.Priorities, rule)
+		}
+	case len(fp.Priorities) > 0:
+		rules, err := priorityRulesFromMap(fp.Priorities)
+		if err != nil {
+			return SyncPair{}, i18n.Errorf(i18n.MsgErrRepo, fp.Repo, err)
+		}
+		pair.Priorities = rules
+	}
+
+	if fp.Direction.State != "" {
+		pair.Directions.State = fp.Direction.State
+	}
+	if fp.Direction.Title != "" {
+		pair.Directions.Title = fp.Direction.Title
+	}
+	if fp.Direction.Priority != "" {
+		pair.Directions.Priority = fp.Direction.Priority
+	}
+
+	return pair, nil
+}
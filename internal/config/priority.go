@@ -0,0 +1,186 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github-todoist-sync/internal/i18n"
+)
+
+// DefaultPriority je priorita Todoist úkolu, jehož issue nemá žádný
+// štítek odpovídající pravidlům.
+const DefaultPriority = 1
+
+// PriorityRule přiřazuje štítkům odpovídajícím vzoru prioritu Todoist
+// (1–4). Vzor porovnává celý název štítku bez ohledu na velikost písmen,
+// "*" zastupuje libovolný text a "?" jeden znak.
+type PriorityRule struct {
+	Pattern  string
+	Priority int
+	// Label je štítek, který se na issue nastaví, když se priorita změní
+	// v Todoist. Pro vzor bez zástupných znaků je to sám vzor.
+	Label string
+
+	re *regexp.Regexp
+}
+
+func NewPriorityRule(pattern string, priority int, label string) (PriorityRule, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return PriorityRule{}, i18n.Errorf(i18n.MsgErrEmptyPattern)
+	}
+	if priority < 1 || priority > 4 {
+		return PriorityRule{}, i18n.Errorf(i18n.MsgErrPatternPriority, pattern)
+	}
+	if label == "" && !strings.ContainsAny(pattern, "*?") {
+		label = pattern
+	}
+
+	expr := regexp.QuoteMeta(strings.ToLower(pattern))
+	expr = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(expr)
+	return PriorityRule{
+		Pattern:  pattern,
+		Priority: priority,
+		Label:    strings.TrimSpace(label),
+		re:       regexp.MustCompile("^" + expr + "$"),
+	}, nil
+}
+
+func (r PriorityRule) Match(label string) bool {
+	return r.re.MatchString(strings.ToLower(label))
+}
+
+// PriorityRules jsou pravidla v pořadí podle konfigurace. Má-li issue více
+// prioritních štítků, rozhoduje první pravidlo, kterému odpovídá některý
+// z nich; na pořadí štítků na issue nezáleží.
+type PriorityRules []PriorityRule
+
+// DefaultPriorityRules odpovídají štítkům urgent, high, medium a low.
+func DefaultPriorityRules() PriorityRules {
+	var rules PriorityRules
+	for _, d := range []struct {
+		label    string
+		priority int
+	}{{"urgent", 4}, {"high", 3}, {"medium", 2}, {"low", 1}} {
+		rule, _ := NewPriorityRule(d.label, d.priority, "")
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// Priority vrací prioritu podle prvního pravidla, kterému odpovídá některý
+// ze štítků, jinak DefaultPriority.
+func (rules PriorityRules) Priority(labels []string) int {
+	for _, rule := range rules {
+		for _, label := range labels {
+			if rule.Match(label) {
+				return rule.Priority
+			}
+		}
+	}
+	return DefaultPriority
+}
+
+// Relabel určí, jak změnit štítky issue, aby odpovídaly prioritě: remove
+// jsou prioritní štítky, které je třeba odebrat, add štítek prvního
+// pravidla s danou prioritou, pokud ho issue ještě nemá. Pro
+// DefaultPriority žádný štítek nepřidává. Ostatních štítků se změna
+// netýká. Pokud pro prioritu žádné pravidlo se štítkem není, vrací false.
+func (rules PriorityRules) Relabel(labels []string, priority int) (remove []string, add string, ok bool) {
+	var target string
+	if priority != DefaultPriority {
+		for _, rule := range rules {
+			if rule.Priority == priority && rule.Label != "" {
+				target = rule.Label
+				break
+			}
+		}
+		if target == "" {
+			return nil, "", false
+		}
+	}
+
+	add = target
+	for _, label := range labels {
+		switch {
+		case target != "" && strings.EqualFold(label, target):
+			add = ""
+		case rules.matchAny(label):
+			remove = append(remove, label)
+		}
+	}
+	return remove, add, true
+}
+
+func (rules PriorityRules) matchAny(label string) bool {
+	for _, rule := range rules {
+		if rule.Match(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate ověří, že štítek nastavený podle každého pravidla znamená zpět
+// stejnou prioritu. Jinak by se priorita mezi GitHubem a Todoist přelévala
+// tam a zpět.
+func (rules PriorityRules) validate() error {
+	for _, rule := range rules {
+		if rule.Label == "" {
+			continue
+		}
+		if priority := rules.Priority([]string{rule.Label}); priority != rule.Priority {
+			return i18n.Errorf(i18n.MsgErrRuleLabel, rule.Label, rule.Pattern, priority, rule.Priority)
+		}
+	}
+	return nil
+}
+
+// parsePriorityRules čte pravidla ve tvaru "vzor=priorita" oddělená
+// čárkou, např. "P0=4,sev1=4,priority: high=3".
+func parsePriorityRules(value string) (PriorityRules, error) {
+	var rules PriorityRules
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		pattern, priorityPart, found := strings.Cut(entry, "=")
+		priority, err := strconv.Atoi(strings.TrimSpace(priorityPart))
+		if !found || err != nil {
+			return nil, i18n.Errorf(i18n.MsgErrPriorityRule, entry)
+		}
+		rule, err := NewPriorityRule(pattern, priority, "")
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// priorityRulesFromMap převede starší zápis "štítek: priorita". Mapa nemá
+// pořadí, proto vyhrává vyšší priorita a při shodě abecedně první štítek.
+func priorityRulesFromMap(priorities map[string]int) (PriorityRules, error) {
+	labels := make([]string, 0, len(priorities))
+	for label := range priorities {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if priorities[labels[i]] != priorities[labels[j]] {
+			return priorities[labels[i]] > priorities[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	var rules PriorityRules
+	for _, label := range labels {
+		rule, err := NewPriorityRule(label, priorities[label], "")
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func mustParseRules(t *testing.T, value string) PriorityRules {
+	t.Helper()
+	rules, err := parsePriorityRules(value)
+	if err != nil {
+		t.Fatalf("parsePriorityRules(%q) error = %v", value, err)
+	}
+	return rules
+}
+
+func TestPriorityRuleOrder(t *testing.T) {
+	rules := mustParseRules(t, "P0=4,sev1=4,priority: high=3,sev*=2")
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   int
+	}{
+		{"first rule wins over label order", []string{"sev2", "P0"}, 4},
+		{"exact before wildcard", []string{"sev3", "sev1"}, 4},
+		{"wildcard", []string{"bug", "sev2"}, 2},
+		{"case insensitive", []string{"Priority: HIGH"}, 3},
+		{"several rules, highest listed first", []string{"sev2", "priority: high"}, 3},
+		{"no priority label", []string{"bug"}, DefaultPriority},
+		{"no labels", nil, DefaultPriority},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Priority(tt.labels); got != tt.want {
+				t.Errorf("Priority(%q) = %d, want %d", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriorityRulesFromMapOrder(t *testing.T) {
+	rules, err := priorityRulesFromMap(map[string]int{"low": 1, "high": 3, "blocker": 4, "critical": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Vyšší priorita má přednost, při shodě abecedně první štítek.
+	var got []string
+	for _, rule := range rules {
+		got = append(got, rule.Pattern)
+	}
+	want := []string{"blocker", "critical", "high", "low"}
+	if !slices.Equal(got, want) {
+		t.Errorf("priorityRulesFromMap() patterns = %q, want %q", got, want)
+	}
+	if got := rules.Priority([]string{"low", "high"}); got != 3 {
+		t.Errorf("Priority(low, high) = %d, want 3", got)
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	defaults := DefaultPriorityRules()
+	// Pro prioritu 4 je jen vzor se zástupným znakem, tedy bez štítku.
+	wildcard := mustParseRules(t, "sev*=4,high=3")
+
+	tests := []struct {
+		name       string
+		rules      PriorityRules
+		labels     []string
+		priority   int
+		wantRemove []string
+		wantAdd    string
+		wantOK     bool
+	}{
+		{"priority 1 removes all priority labels", defaults, []string{"bug", "High", "urgent"}, 1, []string{"High", "urgent"}, "", true},
+		{"priority 1 without priority labels", defaults, []string{"bug"}, 1, nil, "", true},
+		{"target already present", defaults, []string{"bug", "High"}, 3, nil, "", true},
+		{"target present next to another priority", defaults, []string{"urgent", "high"}, 3, []string{"urgent"}, "", true},
+		{"replace priority label", defaults, []string{"urgent", "bug"}, 3, []string{"urgent"}, "high", true},
+		{"issue without labels", defaults, nil, 2, nil, "medium", true},
+		{"no rule with a label", wildcard, []string{"high"}, 4, nil, "", false},
+		{"no rule for the priority", wildcard, []string{"sev1"}, 2, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remove, add, ok := tt.rules.Relabel(tt.labels, tt.priority)
+			if ok != tt.wantOK {
+				t.Fatalf("Relabel(%q, %d) ok = %v, want %v", tt.labels, tt.priority, ok, tt.wantOK)
+			}
+			if !slices.Equal(remove, tt.wantRemove) || add != tt.wantAdd {
+				t.Errorf("Relabel(%q, %d) = %q, %q, want %q, %q",
+					tt.labels, tt.priority, remove, add, tt.wantRemove, tt.wantAdd)
+			}
+		})
+	}
+}
+
+func TestPriorityRulesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"defaults", "urgent=4,high=3,medium=2,low=1", false},
+		{"wildcard without label", "sev*=3,P0=4", false},
+		{"label shadowed by wildcard", "sev*=2,sev1=4", true},
+		{"label shadowed by duplicate", "high=3,High=4", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mustParseRules(t, tt.value).validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePriorityRulesInvalid(t *testing.T) {
+	for _, value := range []string{"P0", "P0=high", "P0=5", "=3"} {
+		if _, err := parsePriorityRules(value); err == nil {
+			t.Errorf("parsePriorityRules(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestDefaultDirectionsFromEnv(t *testing.T) {
+	t.Setenv("STATE_DIRECTION", "")
+	t.Setenv("TITLE_DIRECTION", "")
+	t.Setenv("PRIORITY_DIRECTION", "")
+	want := Directions{State: DirectionBoth, Title: DirectionToTodoist, Priority: DirectionToTodoist}
+	if got := defaultDirections(); got != want {
+		t.Errorf("defaultDirections() = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("PRIORITY_DIRECTION", string(DirectionBoth))
+	want.Priority = DirectionBoth
+	if got := defaultDirections(); got != want {
+		t.Errorf("defaultDirections() with PRIORITY_DIRECTION = %+v, want %+v", got, want)
+	}
+}
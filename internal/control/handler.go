@@ -0,0 +1,108 @@
+package control
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github-todoist-sync/internal/daemon"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+)
+
+// Daemon je rozhraní běžícího daemonu, které řídicí API ovládá.
+type Daemon interface {
+	Enqueue(job daemon.Job) bool
+	Pause()
+	Resume()
+	Status() daemon.Status
+}
+
+var issueRef = regexp.MustCompile(`^([^/\s]+)/([^/#\s]+)#(\d+)$`)
+
+// NewHandler vrací řídicí API daemonu:
+//
+//	GET  /status           stav daemonu, probíhající běh a poslední výsledek
+//	POST /sync             okamžitá úplná synchronizace
+//	POST /sync?issue=o/r#N synchronizace jednoho issue
+//	POST /pause, /resume   pozastavení a obnovení pravidelné synchronizace
+//
+// API nemá autentizaci, proto má naslouchat jen na lokální adrese.
+func NewHandler(d Daemon) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, http.StatusOK, d.Status())
+	})
+
+	mux.HandleFunc("/sync", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		job := daemon.Job{Full: true}
+		if ref := r.URL.Query().Get("issue"); ref != "" {
+			parsed, err := ParseIssueRef(ref)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			job = parsed
+		}
+
+		logging.Info(i18n.MsgControlSync, "job", job.String())
+		if !d.Enqueue(job) {
+			http.Error(w, "queue full", http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, d.Status())
+	})
+
+	mux.HandleFunc("/pause", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		logging.Info(i18n.MsgControlPause)
+		d.Pause()
+		writeJSON(w, http.StatusOK, d.Status())
+	})
+
+	mux.HandleFunc("/resume", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		logging.Info(i18n.MsgControlResume)
+		d.Resume()
+		writeJSON(w, http.StatusOK, d.Status())
+	})
+
+	return mux
+}
+
+// ParseIssueRef převede odkaz ve tvaru owner/repo#číslo na požadavek
+// synchronizace jednoho issue.
+func ParseIssueRef(ref string) (daemon.Job, error) {
+	m := issueRef.FindStringSubmatch(ref)
+	if m == nil {
+		return daemon.Job{}, i18n.Errorf(i18n.MsgErrIssueRef, ref)
+	}
+	number, err := strconv.Atoi(m[3])
+	if err != nil || number <= 0 {
+		return daemon.Job{}, i18n.Errorf(i18n.MsgErrIssueNumber, ref)
+	}
+	return daemon.Job{Owner: m[1], Repo: m[2], Number: number}, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
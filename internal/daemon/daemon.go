@@ -0,0 +1,186 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	gosync "sync"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+	"github-todoist-sync/internal/metrics"
+	"github-todoist-sync/internal/sync"
+	"github-todoist-sync/internal/todoist"
+)
+
+const queueSize = 100
+
+// Job je požadavek na synchronizaci mimo pravidelný interval. Bez čísla
+// issue se synchronizuje celý repozitář, s úkolem se do GitHubu promítnou
+// jeho změny. Full vyžádá okamžitou úplnou synchronizaci.
+type Job struct {
+	Full bool
+
+	Owner  string
+	Repo   string
+	Number int
+	// Rescan načte všechna issues repozitáře, ne jen změněná.
+	Rescan bool
+
+	Task        *todoist.Task
+	TaskDeleted bool
+}
+
+// Daemon spouští pravidelnou úplnou synchronizaci a mezi nimi zpracovává
+// jednotlivé požadavky z fronty. Všechny běhy jdou postupně za sebou, takže
+// se synchronizace nikdy nepřekrývají.
+type Daemon struct {
+	service  *sync.Service
+	interval time.Duration
+	jobs     chan Job
+
+	mu     gosync.Mutex
+	status Status
+}
+
+func New(service *sync.Service, interval time.Duration) *Daemon {
+	return &Daemon{
+		service:  service,
+		interval: interval,
+		jobs:     make(chan Job, queueSize),
+	}
+}
+
+// Enqueue zařadí požadavek do fronty. Pokud je fronta plná, požadavek se
+// zahodí a změnu zachytí až další úplná synchronizace.
+func (d *Daemon) Enqueue(job Job) bool {
+	select {
+	case d.jobs <- job:
+		return true
+	default:
+		logging.Warn(i18n.MsgQueueFull, "job", job.String())
+		return false
+	}
+}
+
+func (d *Daemon) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	// Provedeme první synchronizaci ihned
+	logging.Info(i18n.MsgDaemonInitialSync)
+	d.fullSync(ctx)
+
+	for {
+		// Po zrušení už nezačínáme novou synchronizaci, i když select
+		// vybere tik nebo úlohu z fronty.
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+			if d.Status().Paused {
+				logging.Info(i18n.MsgDaemonPausedTick)
+				continue
+			}
+			logging.Info(i18n.MsgDaemonScheduled)
+			d.fullSync(ctx)
+
+		case job := <-d.jobs:
+			d.runJob(ctx, job)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Pause pozastaví pravidelnou synchronizaci. Požadavky z fronty se
+// zpracovávají dál.
+func (d *Daemon) Pause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.Paused = true
+}
+
+func (d *Daemon) Resume() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.Paused = false
+}
+
+func (d *Daemon) fullSync(ctx context.Context) {
+	started := d.begin(Job{Full: true}.String())
+	result, err := d.service.FullSync(ctx)
+	d.finish(result, err)
+	observe("full", started, result, err)
+	if err != nil {
+		logging.Error(i18n.MsgSyncFailed, "job", "full", logging.Duration(time.Since(started)), logging.Err(err))
+	}
+
+	failure := err
+	if failure == nil && result.Failed() {
+		failure = i18n.Errorf(i18n.MsgErrItemsFailed, len(result.Errors))
+	}
+	d.recordRun(failure)
+}
+
+// String vrací popis požadavku nezávislý na jazyku: "full", "task:ID",
+// owner/repo nebo owner/repo#číslo.
+func (j Job) String() string {
+	if j.Full {
+		return "full"
+	}
+	if j.Task != nil {
+		return "task:" + j.Task.ID
+	}
+	if j.Number == 0 {
+		return j.Owner + "/" + j.Repo
+	}
+	return fmt.Sprintf("%s/%s#%d", j.Owner, j.Repo, j.Number)
+}
+
+func (d *Daemon) runJob(ctx context.Context, job Job) {
+	if job.Full {
+		logging.Info(i18n.MsgDaemonRequested)
+		d.fullSync(ctx)
+		return
+	}
+
+	started := d.begin(job.String())
+
+	var kind string
+	var result *sync.SyncResult
+	var err error
+	switch {
+	case job.Task != nil:
+		kind = "task"
+		result, err = d.service.SyncTask(ctx, job.Task, job.TaskDeleted)
+	case job.Number == 0:
+		kind = "repo"
+		result, err = d.service.SyncRepo(ctx, job.Owner, job.Repo, job.Rescan)
+	default:
+		kind = "issue"
+		result, err = d.service.SyncIssue(ctx, job.Owner, job.Repo, job.Number)
+	}
+
+	d.finish(result, err)
+	observe(kind, started, result, err)
+	if err != nil {
+		logging.Error(i18n.MsgSyncFailed, "job", job.String(), logging.Duration(time.Since(started)), logging.Err(err))
+	}
+}
+
+// observe zaznamená běh do metrik. Běh s chybami jednotlivých položek se
+// počítá jako částečně úspěšný.
+func observe(kind string, started time.Time, result *sync.SyncResult, err error) {
+	outcome := "success"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case result != nil && result.Failed():
+		outcome = "partial"
+	}
+	metrics.ObserveRun(kind, outcome, started)
+}
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/redact"
+	"github-todoist-sync/internal/sync"
+)
+
+// Status je stav daemonu pro health a readiness endpointy a řídicí API.
+type Status struct {
+	Ready               bool      `json:"ready"`
+	Paused              bool      `json:"paused"`
+	LastRun             time.Time `json:"last_run,omitempty"`
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+
+	// Running popisuje právě probíhající běh a Progress jeho průběh,
+	// Queued počet čekajících požadavků ve frontě.
+	Running      string         `json:"running,omitempty"`
+	RunningSince time.Time      `json:"running_since,omitempty"`
+	Progress     *sync.Progress `json:"progress,omitempty"`
+	Queued       int            `json:"queued"`
+
+	// LastResult shrnuje poslední dokončený běh včetně požadavků z fronty.
+	LastResult      string   `json:"last_result,omitempty"`
+	LastItemErrors  []string `json:"last_item_errors,omitempty"`
+	GitHubRateLimit string   `json:"github_rate_limit"`
+}
+
+// Status vrací kopii aktuálního stavu.
+func (d *Daemon) Status() Status {
+	d.mu.Lock()
+	status := d.status
+	status.LastItemErrors = append([]string(nil), d.status.LastItemErrors...)
+	d.mu.Unlock()
+
+	if status.Running != "" {
+		progress := d.service.Progress()
+		status.Progress = &progress
+	}
+	status.Queued = len(d.jobs)
+	status.GitHubRateLimit = d.service.RateLimit().String()
+	return status
+}
+
+// begin zaznamená začátek běhu a vrátí čas jeho spuštění.
+func (d *Daemon) begin(description string) time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.Running = description
+	d.status.RunningSince = time.Now()
+	return d.status.RunningSince
+}
+
+// finish zaznamená výsledek dokončeného běhu.
+func (d *Daemon) finish(result *sync.SyncResult, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.Running = ""
+	d.status.RunningSince = time.Time{}
+	d.status.LastItemErrors = nil
+	switch {
+	case err != nil:
+		d.status.LastResult = i18n.T(i18n.MsgStatusError, redact.Error(err))
+	case result != nil:
+		d.status.LastResult = result.String()
+	}
+	if result != nil {
+		for _, itemErr := range result.Errors {
+			d.status.LastItemErrors = append(d.status.LastItemErrors, redact.Error(itemErr))
+		}
+	}
+}
+
+// recordRun zaznamená výsledek úplné synchronizace. Běh s chybami
+// jednotlivých položek se počítá jako neúspěšný.
+func (d *Daemon) recordRun(failure error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.Ready = true
+	d.status.LastRun = time.Now()
+	if failure != nil {
+		d.status.ConsecutiveFailures++
+		d.status.LastError = redact.Error(failure)
+		return
+	}
+	d.status.ConsecutiveFailures = 0
+	d.status.LastError = ""
+	d.status.LastSuccess = d.status.LastRun
+}
+
+type healthResponse struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
+	Daemon  Status   `json:"daemon"`
+}
+
+// HealthHandler hlásí "degraded" (503), pokud posledních maxFailures
+// synchronizací selhalo nebo je poslední úspěšná starší než maxAge.
+func (d *Daemon) HealthHandler(maxFailures int, maxAge time.Duration) http.Handler {
+	started := time.Now()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := d.Status()
+
+		var reasons []string
+		if maxFailures > 0 && status.ConsecutiveFailures >= maxFailures {
+			reasons = append(reasons, i18n.T(i18n.MsgHealthFailures, status.ConsecutiveFailures, status.LastError))
+		}
+		lastSuccess := status.LastSuccess
+		if lastSuccess.IsZero() {
+			lastSuccess = started
+		}
+		if age := time.Since(lastSuccess); maxAge > 0 && age > maxAge {
+			reasons = append(reasons, i18n.T(i18n.MsgHealthStale, age.Round(time.Second)))
+		}
+
+		writeHealth(w, status, reasons, "degraded")
+	})
+}
+
+// ReadyHandler hlásí připravenost až po dokončení počáteční synchronizace.
+// Projekty a sekce v Todoist jsou v tu chvíli již nastavené, protože bez
+// nich se služba vůbec nespustí.
+func (d *Daemon) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := d.Status()
+
+		var reasons []string
+		if !status.Ready {
+			reasons = append(reasons, i18n.T(i18n.MsgHealthNotReady))
+		}
+
+		writeHealth(w, status, reasons, "not_ready")
+	})
+}
+
+func writeHealth(w http.ResponseWriter, status Status, reasons []string, failed string) {
+	resp := healthResponse{Status: "ok", Reasons: reasons, Daemon: status}
+	code := http.StatusOK
+	if len(reasons) > 0 {
+		resp.Status = failed
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
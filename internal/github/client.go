@@ -3,63 +3,92 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/metrics"
+
 	"github.com/google/go-github/v56/github"
 	"golang.org/x/oauth2"
 )
 
 type Client struct {
 	client *github.Client
-	owner  string
-	repo   string
+
+	mu   sync.Mutex
+	rate RateLimit
 }
 
 type Issue struct {
-	ID          int64
-	Number      int
-	Title       string
-	Body        string
-	State       string
-	Labels      []string
-	Assignee    string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	HTMLURL     string
-	IsPullReq   bool
+	ID        int64
+	Owner     string
+	Repo      string
+	Number    int
+	Title     string
+	Body      string
+	State     string
+	Labels    []string
+	Assignee  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	HTMLURL   string
+	IsPullReq bool
+}
+
+// FullName vrací název repozitáře issue ve tvaru owner/repo.
+func (i *Issue) FullName() string {
+	return i.Owner + "/" + i.Repo
+}
+
+// Ref vrací jednoznačný odkaz na issue ve tvaru owner/repo#číslo.
+func (i *Issue) Ref() string {
+	return fmt.Sprintf("%s#%d", i.FullName(), i.Number)
 }
 
-func NewClient(token, owner, repo string) *Client {
+func NewClient(token string) *Client {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(context.Background(), ts)
-	
+	tc.Transport = metrics.Transport("github", tc.Transport)
+
 	return &Client{
 		client: github.NewClient(tc),
-		owner:  owner,
-		repo:   repo,
 	}
 }
 
-func (c *Client) GetIssues(ctx context.Context) ([]*Issue, error) {
+// PageSize je počet issues na jedné stránce výpisu, nejvíc kolik GitHub
+// dovolí.
+const PageSize = 100
+
+func (c *Client) GetIssues(ctx context.Context, owner, repo string) ([]*Issue, error) {
 	opt := &github.IssueListByRepoOptions{
 		State: "all",
 		ListOptions: github.ListOptions{
-			PerPage: 100,
+			PerPage: PageSize,
 		},
 	}
 
 	var allIssues []*Issue
-	
+
 	for {
-		issues, resp, err := c.client.Issues.ListByRepo(ctx, c.owner, c.repo, opt)
+		var issues []*github.Issue
+		var resp *github.Response
+		err := c.call(ctx, func() (*github.Response, error) {
+			var err error
+			issues, resp, err = c.client.Issues.ListByRepo(ctx, owner, repo, opt)
+			return resp, err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("chyba při získávání issues: %v", err)
+			return nil, i18n.Errorf(i18n.MsgErrGitHubIssues, owner, repo, err)
 		}
 
 		for _, issue := range issues {
-			allIssues = append(allIssues, c.convertIssue(issue))
+			allIssues = append(allIssues, c.convertIssue(owner, repo, issue))
 		}
 
 		if resp.NextPage == 0 {
@@ -71,31 +100,147 @@ func (c *Client) GetIssues(ctx context.Context) ([]*Issue, error) {
 	return allIssues, nil
 }
 
-func (c *Client) GetIssue(ctx context.Context, number int) (*Issue, error) {
-	issue, _, err := c.client.Issues.Get(ctx, c.owner, c.repo, number)
+// IssueList je výsledek přírůstkového načtení issues.
+type IssueList struct {
+	Issues []*Issue
+	// ETag první stránky pro podmíněný požadavek v příštím běhu.
+	ETag string
+	// NotModified značí, že se od minulého požadavku nic nezměnilo.
+	NotModified bool
+}
+
+// GetIssuesSince načte issues změněné od since. S etag z minulého běhu
+// pošle podmíněný požadavek, takže odpověď 304 nic nestojí a nepočítá se
+// do limitu API.
+func (c *Client) GetIssuesSince(ctx context.Context, owner, repo string, since time.Time, etag string) (*IssueList, error) {
+	list := &IssueList{}
+
+	for page := 1; page != 0; {
+		query := url.Values{
+			"state":     {"all"},
+			"sort":      {"updated"},
+			"direction": {"asc"},
+			"since":     {since.UTC().Format(time.RFC3339)},
+			"per_page":  {strconv.Itoa(PageSize)},
+			"page":      {strconv.Itoa(page)},
+		}
+		req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/issues?%s", owner, repo, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		if page == 1 && etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		var issues []*github.Issue
+		var resp *github.Response
+		err = c.call(ctx, func() (*github.Response, error) {
+			var err error
+			resp, err = c.client.Do(ctx, req, &issues)
+			return resp, err
+		})
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			list.ETag = etag
+			list.NotModified = true
+			return list, nil
+		}
+		if err != nil {
+			return nil, i18n.Errorf(i18n.MsgErrGitHubIssues, owner, repo, err)
+		}
+
+		if page == 1 {
+			list.ETag = resp.Header.Get("ETag")
+		}
+		for _, issue := range issues {
+			list.Issues = append(list.Issues, c.convertIssue(owner, repo, issue))
+		}
+		page = resp.NextPage
+	}
+
+	return list, nil
+}
+
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	var issue *github.Issue
+	err := c.call(ctx, func() (resp *github.Response, err error) {
+		issue, resp, err = c.client.Issues.Get(ctx, owner, repo, number)
+		return resp, err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("chyba při získávání issue #%d: %v", number, err)
+		return nil, i18n.Errorf(i18n.MsgErrGitHubIssue, owner, repo, number, err)
 	}
 
-	return c.convertIssue(issue), nil
+	return c.convertIssue(owner, repo, issue), nil
 }
 
-func (c *Client) UpdateIssueState(ctx context.Context, number int, state string) error {
+func (c *Client) UpdateIssueState(ctx context.Context, owner, repo string, number int, state string) error {
 	issueRequest := &github.IssueRequest{
 		State: &state,
 	}
 
-	_, _, err := c.client.Issues.Edit(ctx, c.owner, c.repo, number, issueRequest)
+	err := c.call(ctx, func() (*github.Response, error) {
+		_, resp, err := c.client.Issues.Edit(ctx, owner, repo, number, issueRequest)
+		return resp, err
+	})
+	if err != nil {
+		return i18n.Errorf(i18n.MsgErrGitHubState, owner, repo, number, err)
+	}
+
+	return nil
+}
+
+func (c *Client) UpdateIssueTitle(ctx context.Context, owner, repo string, number int, title string) error {
+	issueRequest := &github.IssueRequest{
+		Title: &title,
+	}
+
+	err := c.call(ctx, func() (*github.Response, error) {
+		_, resp, err := c.client.Issues.Edit(ctx, owner, repo, number, issueRequest)
+		return resp, err
+	})
+	if err != nil {
+		return i18n.Errorf(i18n.MsgErrGitHubTitle, owner, repo, number, err)
+	}
+
+	return nil
+}
+
+// UpdateIssueLabels odebere z issue štítky remove a přidá štítky add.
+// Ostatních štítků se nedotkne, takže nepřepíše ani štítky, které mezitím
+// přidal někdo jiný. Štítek, který už na issue není, se přeskočí.
+func (c *Client) UpdateIssueLabels(ctx context.Context, owner, repo string, number int, remove, add []string) error {
+	for _, label := range remove {
+		err := c.call(ctx, func() (*github.Response, error) {
+			resp, err := c.client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, url.PathEscape(label))
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return resp, nil
+			}
+			return resp, err
+		})
+		if err != nil {
+			return i18n.Errorf(i18n.MsgErrGitHubLabelRemove, label, owner, repo, number, err)
+		}
+	}
+
+	if len(add) == 0 {
+		return nil
+	}
+	err := c.call(ctx, func() (*github.Response, error) {
+		_, resp, err := c.client.Issues.AddLabelsToIssue(ctx, owner, repo, number, add)
+		return resp, err
+	})
 	if err != nil {
-		return fmt.Errorf("chyba při aktualizaci issue #%d: %v", number, err)
+		return i18n.Errorf(i18n.MsgErrGitHubLabelsAdd, add, owner, repo, number, err)
 	}
 
 	return nil
 }
 
-func (c *Client) convertIssue(issue *github.Issue) *Issue {
+func (c *Client) convertIssue(owner, repo string, issue *github.Issue) *Issue {
 	converted := &Issue{
 		ID:        issue.GetID(),
+		Owner:     owner,
+		Repo:      repo,
 		Number:    issue.GetNumber(),
 		Title:     issue.GetTitle(),
 		Body:      issue.GetBody(),
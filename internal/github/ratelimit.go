@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+	"github-todoist-sync/internal/metrics"
+
+	"github.com/google/go-github/v56/github"
+)
+
+const (
+	// rateLimitReserve je počet požadavků, které si necháváme v záloze.
+	// Při jeho dosažení klient počká na obnovení limitu.
+	rateLimitReserve = 10
+	// maxAbuseRetries omezuje opakování po sekundárním limitu.
+	maxAbuseRetries = 3
+	// defaultAbuseWait se použije, když GitHub nepošle Retry-After.
+	defaultAbuseWait = time.Minute
+)
+
+// RateLimit je poslední známý stav primárního limitu GitHub API.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+func (r RateLimit) String() string {
+	if r.Limit == 0 {
+		return i18n.T(i18n.MsgRateLimitUnknown)
+	}
+	return i18n.T(i18n.MsgRateLimitSummary,
+		r.Remaining, r.Limit, r.Reset.Local().Format("15:04:05"))
+}
+
+func (r RateLimit) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("limit", r.Limit),
+		slog.Int("remaining", r.Remaining),
+		slog.Time("reset", r.Reset),
+	)
+}
+
+// RateLimit vrací stav limitu z posledně přijaté odpovědi.
+func (c *Client) RateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rate
+}
+
+// call provede volání API s ohledem na limity. Před voláním počká, pokud
+// je primární limit téměř vyčerpaný. Po sekundárním limitu (abuse) počká
+// podle Retry-After a volání zopakuje, po vyčerpání primárního limitu
+// počká do jeho obnovení.
+func (c *Client) call(ctx context.Context, fn func() (*github.Response, error)) error {
+	for attempt := 1; ; attempt++ {
+		if err := c.waitForQuota(ctx); err != nil {
+			return err
+		}
+
+		resp, err := fn()
+		if resp != nil {
+			c.record(resp.Rate)
+		}
+		if err == nil {
+			return nil
+		}
+
+		var abuseErr *github.AbuseRateLimitError
+		var rateErr *github.RateLimitError
+		switch {
+		case errors.As(err, &abuseErr) && attempt <= maxAbuseRetries:
+			wait := defaultAbuseWait
+			if abuseErr.RetryAfter != nil {
+				wait = *abuseErr.RetryAfter
+			}
+			logging.Warn(i18n.MsgAbuseLimitWait, "wait", wait, "attempt", attempt, "max_attempts", maxAbuseRetries)
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+		case errors.As(err, &rateErr) && attempt == 1:
+			c.record(rateErr.Rate)
+		default:
+			return err
+		}
+	}
+}
+
+func (c *Client) record(rate github.Rate) {
+	if rate.Limit == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rate = RateLimit{
+		Limit:     rate.Limit,
+		Remaining: rate.Remaining,
+		Reset:     rate.Reset.Time,
+	}
+	metrics.SetRateLimitRemaining("github", rate.Remaining)
+}
+
+func (c *Client) waitForQuota(ctx context.Context) error {
+	rate := c.RateLimit()
+	if rate.Limit == 0 || rate.Remaining > rateLimitReserve {
+		return nil
+	}
+
+	wait := time.Until(rate.Reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	logging.Warn(i18n.MsgRateLimitWait, "remaining", rate.Remaining, "wait", wait.Round(time.Second))
+	if err := sleep(ctx, wait); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.rate.Remaining = c.rate.Limit
+	c.mu.Unlock()
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
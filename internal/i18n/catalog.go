@@ -0,0 +1,582 @@
+package i18n
+
+// Zprávy logu. Text zprávy je krátký a neměnný, proměnné údaje se
+// předávají jako pole záznamu.
+const (
+	MsgConfigLoadFailed   Message = "config.load_failed"
+	MsgConfigPair         Message = "config.pair"
+	MsgConfigInterval     Message = "config.interval"
+	MsgServiceInitFailed  Message = "service.init_failed"
+	MsgServerSetupFailed  Message = "server.setup_failed"
+	MsgServerFailed       Message = "server.failed"
+	MsgWebhooksEnabled    Message = "server.webhooks_enabled"
+	MsgMetricsEnabled     Message = "server.metrics_enabled"
+	MsgControlListening   Message = "server.control_listening"
+	MsgSignalReceived     Message = "signal.received"
+	MsgRunOnce            Message = "run.once"
+	MsgRunGitHubOnly      Message = "run.github_only"
+	MsgRunTodoistOnly     Message = "run.todoist_only"
+	MsgRunDaemon          Message = "run.daemon"
+	MsgRunServer          Message = "run.server"
+	MsgRunDone            Message = "run.done"
+	MsgRunDoneWithErrors  Message = "run.done_with_errors"
+	MsgRunResult          Message = "run.result"
+	MsgRunSkipped         Message = "run.skipped"
+	MsgRunItemFailed      Message = "run.item_failed"
+	MsgDaemonStarted      Message = "daemon.started"
+	MsgDaemonStopped      Message = "daemon.stopped"
+	MsgDaemonStopTimeout  Message = "daemon.stop_timeout"
+	MsgDaemonInitialSync  Message = "daemon.initial_sync"
+	MsgDaemonScheduled    Message = "daemon.scheduled_sync"
+	MsgDaemonPausedTick   Message = "daemon.paused_tick"
+	MsgDaemonRequested    Message = "daemon.requested_sync"
+	MsgQueueFull          Message = "daemon.queue_full"
+	MsgSyncFailed         Message = "sync.failed"
+	MsgControlSync        Message = "control.sync"
+	MsgControlPause       Message = "control.pause"
+	MsgControlResume      Message = "control.resume"
+	MsgWebhookRejected    Message = "webhook.rejected"
+	MsgWebhookGitHub      Message = "webhook.github"
+	MsgWebhookTodoist     Message = "webhook.todoist"
+	MsgPairProject        Message = "setup.pair_project"
+	MsgProjectExisting    Message = "setup.project_existing"
+	MsgProjectCreating    Message = "setup.project_creating"
+	MsgProjectCreated     Message = "setup.project_created"
+	MsgSectionCreating    Message = "setup.section_creating"
+	MsgFullSyncStarted    Message = "sync.full_started"
+	MsgFullSyncDone       Message = "sync.full_done"
+	MsgToTodoistStarted   Message = "sync.to_todoist_started"
+	MsgToTodoistDone      Message = "sync.to_todoist_done"
+	MsgToGitHubStarted    Message = "sync.to_github_started"
+	MsgToGitHubDone       Message = "sync.to_github_done"
+	MsgRepoSync           Message = "sync.repo"
+	MsgConflict           Message = "sync.conflict"
+	MsgIssuesLoaded       Message = "github.issues_loaded"
+	MsgIssuesNotModified  Message = "github.issues_not_modified"
+	MsgIssueFetchFailed   Message = "github.issue_fetch_failed"
+	MsgIssuesFetchFailed  Message = "github.issues_fetch_failed"
+	MsgIssueMissing       Message = "github.issue_missing"
+	MsgAbuseLimitWait     Message = "github.abuse_limit_wait"
+	MsgRateLimitWait      Message = "github.rate_limit_wait"
+	MsgTasksLoaded        Message = "todoist.tasks_loaded"
+	MsgTaskDeleted        Message = "todoist.task_deleted"
+	MsgTodoistRetry       Message = "todoist.retry"
+	MsgLinkRestored       Message = "link.restored"
+	MsgActionFailed       Message = "action.failed"
+	MsgTaskCreated        Message = "action.task_created"
+	MsgTaskUpdated        Message = "action.task_updated"
+	MsgTaskClosed         Message = "action.task_closed"
+	MsgTaskReopened       Message = "action.task_reopened"
+	MsgIssueClosed        Message = "action.issue_closed"
+	MsgIssueReopened      Message = "action.issue_reopened"
+	MsgIssueTitleUpdated  Message = "action.issue_title_updated"
+	MsgIssueLabelsUpdated Message = "action.issue_labels_updated"
+	MsgDebugEnabled       Message = "debug.enabled"
+	MsgResultSummary      Message = "result.summary"
+	MsgRateLimitUnknown   Message = "ratelimit.unknown"
+	MsgRateLimitSummary   Message = "ratelimit.summary"
+	MsgPlanHeader         Message = "plan.header"
+	MsgPlanEmpty          Message = "plan.empty"
+	MsgPlanTotal          Message = "plan.total"
+	MsgCtlUsage           Message = "ctl.usage"
+	MsgCtlAddrFlag        Message = "ctl.addr_flag"
+	MsgCtlFailed          Message = "ctl.failed"
+	MsgCtlInvalidResponse Message = "ctl.invalid_response"
+	MsgCtlSyncQueued      Message = "ctl.sync_queued"
+	MsgCtlPaused          Message = "ctl.paused"
+	MsgCtlResumed         Message = "ctl.resumed"
+	MsgStatusSchedule     Message = "status.schedule"
+	MsgStatusActive       Message = "status.active"
+	MsgStatusPaused       Message = "status.paused"
+	MsgStatusRunning      Message = "status.running"
+	MsgStatusProgress     Message = "status.progress"
+	MsgStatusProgressLine Message = "status.progress_line"
+	MsgPhaseToTodoist     Message = "phase.to_todoist"
+	MsgPhaseToGitHub      Message = "phase.to_github"
+	MsgStatusQueued       Message = "status.queued"
+	MsgStatusLastRun      Message = "status.last_run"
+	MsgStatusLastSuccess  Message = "status.last_success"
+	MsgStatusLastResult   Message = "status.last_result"
+	MsgStatusItemError    Message = "status.item_error"
+	MsgStatusFailures     Message = "status.failures"
+	MsgStatusError        Message = "status.error"
+	MsgHealthFailures     Message = "health.failures"
+	MsgHealthStale        Message = "health.stale"
+	MsgHealthNotReady     Message = "health.not_ready"
+	MsgConflictDetail     Message = "conflict.detail"
+	MsgConflictTodoist    Message = "conflict.todoist_wins"
+	MsgConflictGitHub     Message = "conflict.github_wins"
+	MsgConflictManual     Message = "conflict.manual"
+	MsgConflictNoTaskTime Message = "conflict.no_task_time"
+	MsgConflictNewerTask  Message = "conflict.newer_task"
+	MsgConflictNewerIssue Message = "conflict.newer_issue"
+	MsgSkipTaskDeleted    Message = "skip.task_deleted"
+	MsgDetailTitle        Message = "detail.title"
+	MsgDetailPriority     Message = "detail.priority"
+	MsgDetailLabels       Message = "detail.labels"
+	MsgFlagMode           Message = "flag.mode"
+	MsgFlagVerbose        Message = "flag.verbose"
+	MsgFlagConfig         Message = "flag.config"
+	MsgFlagDryRun         Message = "flag.dry_run"
+	MsgFlagOutput         Message = "flag.output"
+	MsgUsageDryRunMode    Message = "usage.dry_run_mode"
+	MsgUsageOutput        Message = "usage.output"
+	MsgUsageMode          Message = "usage.mode"
+)
+
+// Chybové zprávy. Text se přeloží až při výpisu chyby, viz Errorf.
+const (
+	MsgErrEmptyPattern      Message = "error.empty_pattern"
+	MsgErrPatternPriority   Message = "error.pattern_priority"
+	MsgErrRuleLabel         Message = "error.rule_label"
+	MsgErrPriorityRule      Message = "error.priority_rule"
+	MsgErrConfigRead        Message = "error.config_read"
+	MsgErrConfigParse       Message = "error.config_parse"
+	MsgErrRepoInvalid       Message = "error.repo_invalid"
+	MsgErrRepoFormat        Message = "error.repo_format"
+	MsgErrRepoDuplicate     Message = "error.repo_duplicate"
+	MsgErrRepo              Message = "error.repo"
+	MsgErrPriorityForms     Message = "error.priority_forms"
+	MsgErrGitHubToken       Message = "error.github_token"
+	MsgErrRepos             Message = "error.repos"
+	MsgErrTodoistToken      Message = "error.todoist_token"
+	MsgErrConcurrency       Message = "error.concurrency"
+	MsgErrRetryCount        Message = "error.retry_count"
+	MsgErrRetryWait         Message = "error.retry_wait"
+	MsgErrLogFormat         Message = "error.log_format"
+	MsgErrLanguage          Message = "error.language"
+	MsgErrProjectMissing    Message = "error.project_missing"
+	MsgErrDirection         Message = "error.direction"
+	MsgErrConflictPolicy    Message = "error.conflict_policy"
+	MsgErrStateFilter       Message = "error.state_filter"
+	MsgErrServerSecrets     Message = "error.server_secrets"
+	MsgErrItemsFailed       Message = "error.items_failed"
+	MsgErrIssueRef          Message = "error.issue_ref"
+	MsgErrIssueNumber       Message = "error.issue_number"
+	MsgErrSkipped           Message = "error.skipped"
+	MsgErrUnknownAction     Message = "error.unknown_action"
+	MsgErrStoreOpen         Message = "error.store_open"
+	MsgErrStoreRead         Message = "error.store_read"
+	MsgErrStoreParse        Message = "error.store_parse"
+	MsgErrStoreSave         Message = "error.store_save"
+	MsgErrProjectSetup      Message = "error.project_setup"
+	MsgErrProjectCreate     Message = "error.project_create"
+	MsgErrSectionCreate     Message = "error.section_create"
+	MsgErrRepoNotSynced     Message = "error.repo_not_synced"
+	MsgErrIssuesFetch       Message = "error.issues_fetch"
+	MsgErrIssueUnavailable  Message = "error.issue_unavailable"
+	MsgErrToTodoist         Message = "error.to_todoist"
+	MsgErrToGitHub          Message = "error.to_github"
+	MsgErrTodoistTasks      Message = "error.todoist_tasks"
+	MsgErrCommandNoResult   Message = "error.command_no_result"
+	MsgErrCommandStatus     Message = "error.command_status"
+	MsgErrBatchSize         Message = "error.batch_size"
+	MsgErrBatchSend         Message = "error.batch_send"
+	MsgErrProjectsGet       Message = "error.projects_get"
+	MsgErrProjectNotFound   Message = "error.project_not_found"
+	MsgErrProjectPost       Message = "error.project_post"
+	MsgErrSectionsGet       Message = "error.sections_get"
+	MsgErrSectionPost       Message = "error.section_post"
+	MsgErrTasksGet          Message = "error.tasks_get"
+	MsgErrCompletedGet      Message = "error.completed_get"
+	MsgErrTaskCreate        Message = "error.task_create"
+	MsgErrTaskUpdate        Message = "error.task_update"
+	MsgErrTaskClose         Message = "error.task_close"
+	MsgErrTaskReopen        Message = "error.task_reopen"
+	MsgErrTasksSync         Message = "error.tasks_sync"
+	MsgErrGitHubIssues      Message = "error.github_issues"
+	MsgErrGitHubIssue       Message = "error.github_issue"
+	MsgErrGitHubState       Message = "error.github_state"
+	MsgErrGitHubTitle       Message = "error.github_title"
+	MsgErrGitHubLabelRemove Message = "error.github_label_remove"
+	MsgErrGitHubLabelsAdd   Message = "error.github_labels_add"
+)
+
+var catalogs = map[string]map[Message]string{
+	"cs": {
+		MsgConfigLoadFailed:   "Chyba při načítání konfigurace",
+		MsgConfigPair:         "Propojení repozitáře s projektem",
+		MsgConfigInterval:     "Interval synchronizace",
+		MsgServiceInitFailed:  "Chyba při inicializaci služby",
+		MsgServerSetupFailed:  "Chyba při nastavování serveru",
+		MsgServerFailed:       "Chyba HTTP serveru",
+		MsgWebhooksEnabled:    "Přijímám webhooky",
+		MsgMetricsEnabled:     "Zpřístupňuji metriky",
+		MsgControlListening:   "Řídicí API naslouchá",
+		MsgSignalReceived:     "Přijat signál, ukončuji",
+		MsgRunOnce:            "Spouštím jednorázovou synchronizaci",
+		MsgRunGitHubOnly:      "Spouštím synchronizaci pouze GitHub → Todoist",
+		MsgRunTodoistOnly:     "Spouštím synchronizaci pouze Todoist → GitHub",
+		MsgRunDaemon:          "Spouštím službu v režimu daemon",
+		MsgRunServer:          "Spouštím službu v režimu server",
+		MsgRunDone:            "Synchronizace dokončena",
+		MsgRunDoneWithErrors:  "Synchronizace dokončena s chybami",
+		MsgRunResult:          "Výsledek synchronizace",
+		MsgRunSkipped:         "Přeskočeno",
+		MsgRunItemFailed:      "Položku se nepodařilo synchronizovat",
+		MsgDaemonStarted:      "Daemon spuštěn, pro ukončení stiskněte Ctrl+C",
+		MsgDaemonStopped:      "Daemon ukončen",
+		MsgDaemonStopTimeout:  "Synchronizace se neukončila včas, končím bez čekání",
+		MsgDaemonInitialSync:  "Provádím počáteční synchronizaci",
+		MsgDaemonScheduled:    "Spouštím plánovanou synchronizaci",
+		MsgDaemonPausedTick:   "Plánovaná synchronizace je pozastavena",
+		MsgDaemonRequested:    "Spouštím vyžádanou synchronizaci",
+		MsgQueueFull:          "Fronta synchronizace je plná, zahazuji požadavek",
+		MsgSyncFailed:         "Chyba při synchronizaci",
+		MsgControlSync:        "Řídicí API: požadavek na synchronizaci",
+		MsgControlPause:       "Řídicí API: pozastavuji pravidelnou synchronizaci",
+		MsgControlResume:      "Řídicí API: obnovuji pravidelnou synchronizaci",
+		MsgWebhookRejected:    "Odmítnut webhook s neplatným podpisem",
+		MsgWebhookGitHub:      "Přijat GitHub webhook",
+		MsgWebhookTodoist:     "Přijat Todoist webhook",
+		MsgPairProject:        "Repozitář se synchronizuje do Todoist projektu",
+		MsgProjectExisting:    "Používám existující Todoist projekt",
+		MsgProjectCreating:    "Vytvářím nový Todoist projekt",
+		MsgProjectCreated:     "Vytvořen nový Todoist projekt",
+		MsgSectionCreating:    "Vytvářím sekci v projektu",
+		MsgFullSyncStarted:    "Spouštím úplnou synchronizaci",
+		MsgFullSyncDone:       "Úplná synchronizace dokončena",
+		MsgToTodoistStarted:   "Začínám synchronizaci GitHub → Todoist",
+		MsgToTodoistDone:      "Synchronizace GitHub → Todoist dokončena",
+		MsgToGitHubStarted:    "Začínám synchronizaci Todoist → GitHub",
+		MsgToGitHubDone:       "Synchronizace Todoist → GitHub dokončena",
+		MsgRepoSync:           "Synchronizuji repozitář",
+		MsgConflict:           "Konflikt",
+		MsgIssuesLoaded:       "Načtena GitHub issues",
+		MsgIssuesNotModified:  "V repozitáři se od minulé synchronizace nic nezměnilo",
+		MsgIssueFetchFailed:   "Chyba při získávání issue",
+		MsgIssuesFetchFailed:  "Chyba při získávání issues",
+		MsgIssueMissing:       "Issue už v repozitáři není (smazáno nebo přesunuto), úkol se dál nesynchronizuje",
+		MsgAbuseLimitWait:     "GitHub sekundární limit, čekám",
+		MsgRateLimitWait:      "Docházejí požadavky GitHub API, čekám na obnovení limitu",
+		MsgTasksLoaded:        "Načteny Todoist úkoly",
+		MsgTaskDeleted:        "Úkol byl v Todoist smazán, propojení ponechávám bez úkolu",
+		MsgTodoistRetry:       "Požadavek na Todoist selhal, opakuji",
+		MsgLinkRestored:       "Obnovuji propojení issue s úkolem z popisu úkolu",
+		MsgActionFailed:       "Chyba při akci",
+		MsgTaskCreated:        "Vytvořen úkol",
+		MsgTaskUpdated:        "Aktualizován úkol",
+		MsgTaskClosed:         "Uzavřen úkol",
+		MsgTaskReopened:       "Znovu otevřen úkol",
+		MsgIssueClosed:        "Uzavřeno GitHub issue (dokončeno v Todoist)",
+		MsgIssueReopened:      "Otevřeno GitHub issue (znovu otevřeno v Todoist)",
+		MsgIssueTitleUpdated:  "Změněn název GitHub issue podle Todoist",
+		MsgIssueLabelsUpdated: "Změněny prioritní štítky GitHub issue podle Todoist",
+		MsgDebugEnabled:       "Debug režim zapnut",
+		MsgResultSummary:      "vytvořeno %d, aktualizováno %d, uzavřeno %d, znovu otevřeno %d, přeskočeno %d, chyb %d",
+		MsgRateLimitUnknown:   "limit GitHub API zatím není znám",
+		MsgRateLimitSummary:   "GitHub API: zbývá %d/%d požadavků, obnova v %s",
+		MsgPlanHeader:         "AKCE\tREPOZITÁŘ\tISSUE\tÚKOL\tDETAIL",
+		MsgPlanEmpty:          "Žádné plánované změny",
+		MsgPlanTotal:          "Celkem plánovaných změn: %d",
+		MsgCtlAddrFlag:        "Adresa řídicího API (výchozí CONTROL_ADDR nebo 127.0.0.1:8081)",
+		MsgCtlFailed:          "Chyba řídicího API: %v",
+		MsgCtlInvalidResponse: "neplatná odpověď: %v",
+		MsgCtlSyncQueued:      "Synchronizace zařazena do fronty",
+		MsgCtlPaused:          "Pravidelná synchronizace pozastavena",
+		MsgCtlResumed:         "Pravidelná synchronizace obnovena",
+		MsgStatusSchedule:     "Pravidelná synchronizace:",
+		MsgStatusActive:       "běží",
+		MsgStatusPaused:       "pozastaveno",
+		MsgStatusRunning:      "Právě probíhá:",
+		MsgStatusProgress:     "Průběh:",
+		MsgStatusProgressLine: "%s, repozitáře %d/%d, akce %d/%d",
+		MsgPhaseToTodoist:     "GitHub → Todoist",
+		MsgPhaseToGitHub:      "Todoist → GitHub",
+		MsgStatusQueued:       "Požadavků ve frontě:",
+		MsgStatusLastRun:      "Poslední synchronizace:",
+		MsgStatusLastSuccess:  "Poslední úspěšná:",
+		MsgStatusLastResult:   "Poslední výsledek:",
+		MsgStatusItemError:    "  chyba:",
+		MsgStatusFailures:     "Neúspěšných za sebou:",
+		MsgStatusError:        "chyba: %s",
+		MsgHealthFailures:     "posledních %d synchronizací selhalo: %s",
+		MsgHealthStale:        "poslední úspěšná synchronizace před %v",
+		MsgHealthNotReady:     "počáteční synchronizace ještě neproběhla",
+		MsgConflictDetail:     "pole %s změněno na GitHubu i v Todoist: %s",
+		MsgConflictTodoist:    "vyhrává Todoist",
+		MsgConflictGitHub:     "vyhrává GitHub",
+		MsgConflictManual:     "ponecháno k ručnímu vyřešení",
+		MsgConflictNoTaskTime: "čas změny v Todoist není znám, vyhrává GitHub",
+		MsgConflictNewerTask:  "vyhrává Todoist (změna %s po GitHubu %s)",
+		MsgConflictNewerIssue: "vyhrává GitHub (změna %s po Todoist %s)",
+		MsgSkipTaskDeleted:    "úkol byl v Todoist smazán",
+		MsgDetailTitle:        "název %q → %q",
+		MsgDetailPriority:     "priorita %d → %v",
+		MsgDetailLabels:       "priorita %d → %d, štítky %v → %v",
+		MsgFlagMode:           "Režim spuštění: 'once', 'daemon', 'server', 'github-only', 'todoist-only'",
+		MsgFlagVerbose:        "Podrobné logování",
+		MsgFlagConfig:         "Cesta ke konfiguračnímu YAML souboru (jinak se použijí proměnné prostředí)",
+		MsgFlagDryRun:         "Jen vypíše plánované změny, nic nezapisuje (režimy once, github-only, todoist-only)",
+		MsgFlagOutput:         "Formát výpisu plánu v režimu dry-run: 'table' nebo 'json'",
+		MsgUsageDryRunMode:    "Přepínač -dry-run nelze použít v režimu %s\n",
+		MsgUsageOutput:        "Neplatný formát výpisu: %s\nPovolené formáty: table, json\n",
+		MsgUsageMode:          "Neplatný režim: %s\nPovolené režimy: once, daemon, server, github-only, todoist-only\n",
+		MsgCtlUsage: `Použití: github-todoist-sync ctl [-addr adresa] příkaz
+
+Příkazy:
+  status              stav daemonu, probíhající běh a poslední výsledek
+  sync                okamžitá úplná synchronizace
+  sync owner/repo#N   synchronizace jednoho issue
+  pause               pozastaví pravidelnou synchronizaci
+  resume              obnoví pravidelnou synchronizaci
+`,
+		MsgErrEmptyPattern:      "prázdný vzor štítku",
+		MsgErrPatternPriority:   "priorita vzoru '%s' musí být 1–4",
+		MsgErrRuleLabel:         "štítek '%s' pravidla '%s' by podle pořadí pravidel znamenal prioritu %d místo %d",
+		MsgErrPriorityRule:      "neplatné pravidlo priority '%s', očekáván tvar vzor=priorita",
+		MsgErrConfigRead:        "chyba při čtení konfiguračního souboru: %v",
+		MsgErrConfigParse:       "chyba při parsování konfiguračního souboru %s: %v",
+		MsgErrRepoInvalid:       "neplatný repozitář '%s', očekáván tvar owner/repo",
+		MsgErrRepoFormat:        "repozitář '%s' musí být ve tvaru owner/repo",
+		MsgErrRepoDuplicate:     "repozitář %s je nastaven vícekrát",
+		MsgErrRepo:              "repozitář %s: %v",
+		MsgErrPriorityForms:     "repozitář %s: priority_rules a priorities nelze použít současně",
+		MsgErrGitHubToken:       "GITHUB_TOKEN je povinný",
+		MsgErrRepos:             "GITHUB_REPOS nebo GITHUB_OWNER a GITHUB_REPO jsou povinné",
+		MsgErrTodoistToken:      "TODOIST_TOKEN je povinný",
+		MsgErrConcurrency:       "počet souběžných workerů musí být alespoň 1",
+		MsgErrRetryCount:        "počet opakování Todoist požadavků nesmí být záporný",
+		MsgErrRetryWait:         "počáteční čekání před opakováním je delší než maximální",
+		MsgErrLogFormat:         "neplatný formát logu '%s', povolené formáty: text, json",
+		MsgErrLanguage:          "nepodporovaný jazyk zpráv '%s', podporované jazyky: %s",
+		MsgErrProjectMissing:    "chybí Todoist projekt",
+		MsgErrDirection:         "neplatný směr '%s' pro pole %s",
+		MsgErrConflictPolicy:    "neplatná politika konfliktů '%s'",
+		MsgErrStateFilter:       "neplatný filtr stavu '%s'",
+		MsgErrServerSecrets:     "režim server vyžaduje GITHUB_WEBHOOK_SECRET nebo TODOIST_CLIENT_SECRET",
+		MsgErrItemsFailed:       "%d položek se nepodařilo synchronizovat",
+		MsgErrIssueRef:          "neplatný odkaz na issue %q, očekávám owner/repo#číslo",
+		MsgErrIssueNumber:       "neplatné číslo issue v %q",
+		MsgErrSkipped:           "přeskočeno kvůli předchozí chybě",
+		MsgErrUnknownAction:     "neznámá akce %s",
+		MsgErrStoreOpen:         "chyba při otevírání úložiště propojení: %v",
+		MsgErrStoreRead:         "chyba při čtení úložiště %s: %v",
+		MsgErrStoreParse:        "chyba při parsování úložiště %s: %v",
+		MsgErrStoreSave:         "chyba při ukládání úložiště: %v",
+		MsgErrProjectSetup:      "chyba při nastavování projektu: %v",
+		MsgErrProjectCreate:     "nepodařilo se vytvořit projekt: %v",
+		MsgErrSectionCreate:     "nepodařilo se vytvořit sekci: %v",
+		MsgErrRepoNotSynced:     "repozitář %s/%s není nastaven pro synchronizaci",
+		MsgErrIssuesFetch:       "chyba při získávání GitHub issues: %v",
+		MsgErrIssueUnavailable:  "issue se nepodařilo načíst",
+		MsgErrToTodoist:         "chyba při synchronizaci GitHub → Todoist: %v",
+		MsgErrToGitHub:          "chyba při synchronizaci Todoist → GitHub: %v",
+		MsgErrTodoistTasks:      "chyba při získávání Todoist úkolů: %v",
+		MsgErrCommandNoResult:   "Todoist nevrátil výsledek příkazu %s",
+		MsgErrCommandStatus:     "neznámý výsledek příkazu: %s",
+		MsgErrBatchSize:         "dávka má %d příkazů, povoleno je nejvýše %d",
+		MsgErrBatchSend:         "chyba při odesílání dávky příkazů: %v",
+		MsgErrProjectsGet:       "chyba při získávání projektů: %v",
+		MsgErrProjectNotFound:   "projekt '%s' nebyl nalezen",
+		MsgErrProjectPost:       "chyba při vytváření projektu: %v",
+		MsgErrSectionsGet:       "chyba při získávání sekcí: %v",
+		MsgErrSectionPost:       "chyba při vytváření sekce: %v",
+		MsgErrTasksGet:          "chyba při získávání úkolů: %v",
+		MsgErrCompletedGet:      "chyba při získávání dokončených úkolů: %v",
+		MsgErrTaskCreate:        "chyba při vytváření úkolu: %v",
+		MsgErrTaskUpdate:        "chyba při aktualizaci úkolu: %v",
+		MsgErrTaskClose:         "chyba při uzavírání úkolu: %v",
+		MsgErrTaskReopen:        "chyba při znovuotevření úkolu: %v",
+		MsgErrTasksSync:         "chyba při synchronizaci úkolů: %v",
+		MsgErrGitHubIssues:      "chyba při získávání issues z %s/%s: %v",
+		MsgErrGitHubIssue:       "chyba při získávání issue %s/%s#%d: %v",
+		MsgErrGitHubState:       "chyba při aktualizaci issue %s/%s#%d: %v",
+		MsgErrGitHubTitle:       "chyba při aktualizaci názvu issue %s/%s#%d: %v",
+		MsgErrGitHubLabelRemove: "chyba při odebírání štítku '%s' z issue %s/%s#%d: %v",
+		MsgErrGitHubLabelsAdd:   "chyba při přidávání štítků %v k issue %s/%s#%d: %v",
+	},
+	"en": {
+		MsgConfigLoadFailed:   "Failed to load configuration",
+		MsgConfigPair:         "Repository paired with project",
+		MsgConfigInterval:     "Sync interval",
+		MsgServiceInitFailed:  "Failed to initialize sync service",
+		MsgServerSetupFailed:  "Failed to set up server",
+		MsgServerFailed:       "HTTP server failed",
+		MsgWebhooksEnabled:    "Accepting webhooks",
+		MsgMetricsEnabled:     "Serving metrics",
+		MsgControlListening:   "Control API listening",
+		MsgSignalReceived:     "Signal received, shutting down",
+		MsgRunOnce:            "Starting one-off sync",
+		MsgRunGitHubOnly:      "Starting GitHub → Todoist sync only",
+		MsgRunTodoistOnly:     "Starting Todoist → GitHub sync only",
+		MsgRunDaemon:          "Starting service in daemon mode",
+		MsgRunServer:          "Starting service in server mode",
+		MsgRunDone:            "Sync finished",
+		MsgRunDoneWithErrors:  "Sync finished with errors",
+		MsgRunResult:          "Sync result",
+		MsgRunSkipped:         "Skipped",
+		MsgRunItemFailed:      "Item failed to sync",
+		MsgDaemonStarted:      "Daemon started, press Ctrl+C to stop",
+		MsgDaemonStopped:      "Daemon stopped",
+		MsgDaemonStopTimeout:  "Sync did not stop in time, exiting without waiting",
+		MsgDaemonInitialSync:  "Running initial sync",
+		MsgDaemonScheduled:    "Starting scheduled sync",
+		MsgDaemonPausedTick:   "Scheduled sync is paused",
+		MsgDaemonRequested:    "Starting requested sync",
+		MsgQueueFull:          "Sync queue is full, dropping request",
+		MsgSyncFailed:         "Sync failed",
+		MsgControlSync:        "Control API: sync requested",
+		MsgControlPause:       "Control API: pausing scheduled sync",
+		MsgControlResume:      "Control API: resuming scheduled sync",
+		MsgWebhookRejected:    "Rejected webhook with invalid signature",
+		MsgWebhookGitHub:      "GitHub webhook received",
+		MsgWebhookTodoist:     "Todoist webhook received",
+		MsgPairProject:        "Repository syncs to Todoist project",
+		MsgProjectExisting:    "Using existing Todoist project",
+		MsgProjectCreating:    "Creating Todoist project",
+		MsgProjectCreated:     "Created Todoist project",
+		MsgSectionCreating:    "Creating section in project",
+		MsgFullSyncStarted:    "Starting full sync",
+		MsgFullSyncDone:       "Full sync finished",
+		MsgToTodoistStarted:   "Starting GitHub → Todoist sync",
+		MsgToTodoistDone:      "GitHub → Todoist sync finished",
+		MsgToGitHubStarted:    "Starting Todoist → GitHub sync",
+		MsgToGitHubDone:       "Todoist → GitHub sync finished",
+		MsgRepoSync:           "Syncing repository",
+		MsgConflict:           "Conflict",
+		MsgIssuesLoaded:       "Loaded GitHub issues",
+		MsgIssuesNotModified:  "Repository unchanged since last sync",
+		MsgIssueFetchFailed:   "Failed to fetch issue",
+		MsgIssuesFetchFailed:  "Failed to fetch issues",
+		MsgIssueMissing:       "Issue no longer in repository (deleted or transferred), task will no longer sync",
+		MsgAbuseLimitWait:     "GitHub secondary rate limit, waiting",
+		MsgRateLimitWait:      "GitHub API quota running low, waiting for reset",
+		MsgTasksLoaded:        "Loaded Todoist tasks",
+		MsgTaskDeleted:        "Task was deleted in Todoist, keeping link without task",
+		MsgTodoistRetry:       "Todoist request failed, retrying",
+		MsgLinkRestored:       "Restoring issue link from task description",
+		MsgActionFailed:       "Action failed",
+		MsgTaskCreated:        "Task created",
+		MsgTaskUpdated:        "Task updated",
+		MsgTaskClosed:         "Task closed",
+		MsgTaskReopened:       "Task reopened",
+		MsgIssueClosed:        "GitHub issue closed (completed in Todoist)",
+		MsgIssueReopened:      "GitHub issue reopened (reopened in Todoist)",
+		MsgIssueTitleUpdated:  "GitHub issue title updated from Todoist",
+		MsgIssueLabelsUpdated: "GitHub issue priority labels updated from Todoist",
+		MsgDebugEnabled:       "Debug mode enabled",
+		MsgResultSummary:      "created %d, updated %d, closed %d, reopened %d, skipped %d, errors %d",
+		MsgRateLimitUnknown:   "GitHub API rate limit not known yet",
+		MsgRateLimitSummary:   "GitHub API: %d/%d requests remaining, resets at %s",
+		MsgPlanHeader:         "ACTION\tREPOSITORY\tISSUE\tTASK\tDETAIL",
+		MsgPlanEmpty:          "No planned changes",
+		MsgPlanTotal:          "Total planned changes: %d",
+		MsgCtlAddrFlag:        "Control API address (default CONTROL_ADDR or 127.0.0.1:8081)",
+		MsgCtlFailed:          "Control API error: %v",
+		MsgCtlInvalidResponse: "invalid response: %v",
+		MsgCtlSyncQueued:      "Sync queued",
+		MsgCtlPaused:          "Scheduled sync paused",
+		MsgCtlResumed:         "Scheduled sync resumed",
+		MsgStatusSchedule:     "Scheduled sync:",
+		MsgStatusActive:       "active",
+		MsgStatusPaused:       "paused",
+		MsgStatusRunning:      "Running:",
+		MsgStatusProgress:     "Progress:",
+		MsgStatusProgressLine: "%s, repositories %d/%d, actions %d/%d",
+		MsgPhaseToTodoist:     "GitHub → Todoist",
+		MsgPhaseToGitHub:      "Todoist → GitHub",
+		MsgStatusQueued:       "Queued requests:",
+		MsgStatusLastRun:      "Last sync:",
+		MsgStatusLastSuccess:  "Last success:",
+		MsgStatusLastResult:   "Last result:",
+		MsgStatusItemError:    "  error:",
+		MsgStatusFailures:     "Consecutive failures:",
+		MsgStatusError:        "error: %s",
+		MsgHealthFailures:     "last %d syncs failed: %s",
+		MsgHealthStale:        "last successful sync %v ago",
+		MsgHealthNotReady:     "initial sync has not completed yet",
+		MsgConflictDetail:     "field %s changed on both GitHub and Todoist: %s",
+		MsgConflictTodoist:    "Todoist wins",
+		MsgConflictGitHub:     "GitHub wins",
+		MsgConflictManual:     "left for manual resolution",
+		MsgConflictNoTaskTime: "Todoist change time unknown, GitHub wins",
+		MsgConflictNewerTask:  "Todoist wins (changed %s, after GitHub %s)",
+		MsgConflictNewerIssue: "GitHub wins (changed %s, after Todoist %s)",
+		MsgSkipTaskDeleted:    "task was deleted in Todoist",
+		MsgDetailTitle:        "title %q → %q",
+		MsgDetailPriority:     "priority %d → %v",
+		MsgDetailLabels:       "priority %d → %d, labels %v → %v",
+		MsgFlagMode:           "Run mode: 'once', 'daemon', 'server', 'github-only', 'todoist-only'",
+		MsgFlagVerbose:        "Verbose logging",
+		MsgFlagConfig:         "Path to the YAML configuration file (environment variables are used otherwise)",
+		MsgFlagDryRun:         "Only print planned changes without writing anything (modes once, github-only, todoist-only)",
+		MsgFlagOutput:         "Dry-run plan output format: 'table' or 'json'",
+		MsgUsageDryRunMode:    "The -dry-run flag cannot be used in mode %s\n",
+		MsgUsageOutput:        "Invalid output format: %s\nAllowed formats: table, json\n",
+		MsgUsageMode:          "Invalid mode: %s\nAllowed modes: once, daemon, server, github-only, todoist-only\n",
+		MsgCtlUsage: `Usage: github-todoist-sync ctl [-addr address] command
+
+Commands:
+  status              daemon status, current run and last result
+  sync                immediate full sync
+  sync owner/repo#N   sync a single issue
+  pause               pause scheduled sync
+  resume              resume scheduled sync
+`,
+		MsgErrEmptyPattern:      "empty label pattern",
+		MsgErrPatternPriority:   "priority of pattern '%s' must be 1–4",
+		MsgErrRuleLabel:         "label '%s' of rule '%s' would mean priority %d instead of %d given the rule order",
+		MsgErrPriorityRule:      "invalid priority rule '%s', expected pattern=priority",
+		MsgErrConfigRead:        "failed to read configuration file: %v",
+		MsgErrConfigParse:       "failed to parse configuration file %s: %v",
+		MsgErrRepoInvalid:       "invalid repository '%s', expected owner/repo",
+		MsgErrRepoFormat:        "repository '%s' must be in the form owner/repo",
+		MsgErrRepoDuplicate:     "repository %s is configured more than once",
+		MsgErrRepo:              "repository %s: %v",
+		MsgErrPriorityForms:     "repository %s: priority_rules and priorities cannot be used together",
+		MsgErrGitHubToken:       "GITHUB_TOKEN is required",
+		MsgErrRepos:             "GITHUB_REPOS or GITHUB_OWNER and GITHUB_REPO are required",
+		MsgErrTodoistToken:      "TODOIST_TOKEN is required",
+		MsgErrConcurrency:       "number of concurrent workers must be at least 1",
+		MsgErrRetryCount:        "number of Todoist request retries must not be negative",
+		MsgErrRetryWait:         "initial retry wait is longer than the maximum",
+		MsgErrLogFormat:         "invalid log format '%s', allowed formats: text, json",
+		MsgErrLanguage:          "unsupported message language '%s', supported languages: %s",
+		MsgErrProjectMissing:    "Todoist project is missing",
+		MsgErrDirection:         "invalid direction '%s' for field %s",
+		MsgErrConflictPolicy:    "invalid conflict policy '%s'",
+		MsgErrStateFilter:       "invalid state filter '%s'",
+		MsgErrServerSecrets:     "server mode requires GITHUB_WEBHOOK_SECRET or TODOIST_CLIENT_SECRET",
+		MsgErrItemsFailed:       "%d items failed to sync",
+		MsgErrIssueRef:          "invalid issue reference %q, expected owner/repo#number",
+		MsgErrIssueNumber:       "invalid issue number in %q",
+		MsgErrSkipped:           "skipped after a previous error",
+		MsgErrUnknownAction:     "unknown action %s",
+		MsgErrStoreOpen:         "failed to open link store: %v",
+		MsgErrStoreRead:         "failed to read store %s: %v",
+		MsgErrStoreParse:        "failed to parse store %s: %v",
+		MsgErrStoreSave:         "failed to save store: %v",
+		MsgErrProjectSetup:      "failed to set up project: %v",
+		MsgErrProjectCreate:     "could not create project: %v",
+		MsgErrSectionCreate:     "could not create section: %v",
+		MsgErrRepoNotSynced:     "repository %s/%s is not configured for sync",
+		MsgErrIssuesFetch:       "failed to fetch GitHub issues: %v",
+		MsgErrIssueUnavailable:  "issue could not be fetched",
+		MsgErrToTodoist:         "GitHub → Todoist sync failed: %v",
+		MsgErrToGitHub:          "Todoist → GitHub sync failed: %v",
+		MsgErrTodoistTasks:      "failed to fetch Todoist tasks: %v",
+		MsgErrCommandNoResult:   "Todoist returned no result for command %s",
+		MsgErrCommandStatus:     "unknown command result: %s",
+		MsgErrBatchSize:         "batch has %d commands, at most %d are allowed",
+		MsgErrBatchSend:         "failed to send command batch: %v",
+		MsgErrProjectsGet:       "failed to get projects: %v",
+		MsgErrProjectNotFound:   "project '%s' not found",
+		MsgErrProjectPost:       "error creating project: %v",
+		MsgErrSectionsGet:       "failed to get sections: %v",
+		MsgErrSectionPost:       "error creating section: %v",
+		MsgErrTasksGet:          "failed to get tasks: %v",
+		MsgErrCompletedGet:      "failed to get completed tasks: %v",
+		MsgErrTaskCreate:        "failed to create task: %v",
+		MsgErrTaskUpdate:        "failed to update task: %v",
+		MsgErrTaskClose:         "failed to close task: %v",
+		MsgErrTaskReopen:        "failed to reopen task: %v",
+		MsgErrTasksSync:         "failed to sync tasks: %v",
+		MsgErrGitHubIssues:      "failed to get issues from %s/%s: %v",
+		MsgErrGitHubIssue:       "failed to get issue %s/%s#%d: %v",
+		MsgErrGitHubState:       "failed to update issue %s/%s#%d: %v",
+		MsgErrGitHubTitle:       "failed to update title of issue %s/%s#%d: %v",
+		MsgErrGitHubLabelRemove: "failed to remove label '%s' from issue %s/%s#%d: %v",
+		MsgErrGitHubLabelsAdd:   "failed to add labels %v to issue %s/%s#%d: %v",
+	},
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Message je identifikátor zprávy v katalogu. Identifikátor se nemění
+// s jazykem, a proto se v logu posílá i jako pole "event".
+type Message string
+
+// DefaultLanguage je jazyk, který se použije bez konfigurace.
+const DefaultLanguage = "cs"
+
+var language atomic.Value
+
+func init() {
+	language.Store(DefaultLanguage)
+}
+
+// SetLanguage nastaví jazyk zpráv. Neznámý jazyk vrátí chybu a nastavení
+// nezmění.
+func SetLanguage(lang string) error {
+	if !Supported(lang) {
+		return Errorf(MsgErrLanguage, lang, strings.Join(Languages(), ", "))
+	}
+	language.Store(lang)
+	return nil
+}
+
+func Language() string {
+	return language.Load().(string)
+}
+
+// Supported říká, zda katalog obsahuje daný jazyk.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// T vrátí text zprávy v nastaveném jazyce, s argumenty ho naformátuje
+// jako fmt.Sprintf. Chybějící překlad nahradí angličtina, chybějící
+// zprávu její identifikátor.
+func T(m Message, args ...interface{}) string {
+	text, ok := catalogs[Language()][m]
+	if !ok {
+		text, ok = catalogs["en"][m]
+	}
+	if !ok {
+		text = string(m)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// localizedError je chyba ze zprávy katalogu. Text se přeloží až při
+// výpisu, takže chyby vzniklé před nastavením jazyka (např. při načítání
+// konfigurace) se vypíší v jazyce nastaveném později.
+type localizedError struct {
+	m    Message
+	args []interface{}
+}
+
+// Errorf vytvoří chybu se zprávou z katalogu, argumenty se formátují jako
+// u T.
+func Errorf(m Message, args ...interface{}) error {
+	return &localizedError{m: m, args: args}
+}
+
+func (e *localizedError) Error() string {
+	return T(e.m, e.args...)
+}
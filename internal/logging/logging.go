@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+)
+
+// Názvy polí záznamu, aby měly stejné údaje ve všech zprávách stejný klíč.
+const (
+	KeyEvent    = "event"
+	KeyRepo     = "repo"
+	KeyIssue    = "issue"
+	KeyTaskID   = "task_id"
+	KeyAction   = "action"
+	KeyDuration = "duration"
+	KeyError    = "error"
+)
+
+// Do nastavení přes Setup se loguje textově na standardní chybový výstup,
+// tajemství se ale skrývají už od začátku.
+func init() {
+	slog.SetDefault(slog.New(newRedactHandler(slog.NewTextHandler(os.Stderr, nil))))
+}
+
+// Options nastavuje výstup logu.
+type Options struct {
+	// Format je "text" nebo "json".
+	Format   string
+	Language string
+	Debug    bool
+	// Source přidá do záznamu soubor a řádek volání.
+	Source bool
+}
+
+// Setup nastaví výchozí slog logger a jazyk zpráv. Každý záznam prochází
+// redactHandler, takže se do logu nedostanou tokeny ani autorizační
+// hlavičky.
+func Setup(w io.Writer, opts Options) error {
+	if opts.Language != "" {
+		if err := i18n.SetLanguage(opts.Language); err != nil {
+			return err
+		}
+	}
+
+	handlerOpts := &slog.HandlerOptions{AddSource: opts.Source, Level: slog.LevelInfo}
+	if opts.Debug {
+		handlerOpts.Level = slog.LevelDebug
+	}
+
+	var handler slog.Handler
+	switch opts.Format {
+	case "", "text":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case "json":
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return i18n.Errorf(i18n.MsgErrLogFormat, opts.Format)
+	}
+
+	slog.SetDefault(slog.New(newRedactHandler(handler)))
+	return nil
+}
+
+func Debug(m i18n.Message, args ...any) { write(slog.LevelDebug, m, args) }
+func Info(m i18n.Message, args ...any)  { write(slog.LevelInfo, m, args) }
+func Warn(m i18n.Message, args ...any)  { write(slog.LevelWarn, m, args) }
+func Error(m i18n.Message, args ...any) { write(slog.LevelError, m, args) }
+
+// Fatal zaloguje chybu a ukončí program s návratovým kódem 1.
+func Fatal(m i18n.Message, args ...any) {
+	write(slog.LevelError, m, args)
+	os.Exit(1)
+}
+
+// write přeloží zprávu a přidá její identifikátor jako pole event. Záznam
+// sestaví sám, aby zdrojový řádek ukazoval na volajícího, ne sem.
+func write(level slog.Level, m i18n.Message, args []any) {
+	logger := slog.Default()
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	record := slog.NewRecord(time.Now(), level, i18n.T(m), pcs[0])
+	record.Add(KeyEvent, string(m))
+	record.Add(args...)
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+func Repo(fullName string) slog.Attr {
+	return slog.String(KeyRepo, fullName)
+}
+
+// Issue je odkaz na issue ve tvaru owner/repo#číslo.
+func Issue(ref string) slog.Attr {
+	return slog.String(KeyIssue, ref)
+}
+
+func TaskID(id string) slog.Attr {
+	return slog.String(KeyTaskID, id)
+}
+
+func Action(action string) slog.Attr {
+	return slog.String(KeyAction, action)
+}
+
+func Duration(d time.Duration) slog.Attr {
+	return slog.Duration(KeyDuration, d)
+}
+
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github-todoist-sync/internal/redact"
+)
+
+// redactHandler skryje tajemství ve zprávě i ve všech polích záznamu dřív,
+// než ho předá dalšímu handleru. Prochází jím i výstup balíku log, protože
+// slog.SetDefault ho přesměruje do výchozího handleru.
+type redactHandler struct {
+	next slog.Handler
+}
+
+func newRedactHandler(next slog.Handler) slog.Handler {
+	return redactHandler{next: next}
+}
+
+func (h redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	clean := slog.NewRecord(r.Time, r.Level, redact.String(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, clean)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = redactAttr(a)
+	}
+	return redactHandler{next: h.next.WithAttrs(clean)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{next: h.next.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redact.String(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		clean := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			clean[i] = redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(clean...)}
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case error:
+			return slog.String(a.Key, redact.Error(x))
+		case fmt.Stringer:
+			return slog.String(a.Key, redact.String(x.String()))
+		default:
+			// Ostatní hodnoty ponecháme beze změny, pokud v jejich
+			// textové podobě nic skrývat nemusíme.
+			text := fmt.Sprint(x)
+			if clean := redact.String(text); clean != text {
+				return slog.String(a.Key, clean)
+			}
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
@@ -0,0 +1,113 @@
+// Package metrics sbírá Prometheus metriky synchronizace a volání API.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "github_todoist_sync"
+
+var (
+	syncRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "runs_total",
+		Help:      "Počet běhů synchronizace podle druhu a výsledku.",
+	}, []string{"kind", "outcome"})
+
+	syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "run_duration_seconds",
+		Help:      "Doba trvání běhu synchronizace.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"kind"})
+
+	lastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unixový čas poslední úplné synchronizace bez chyb.",
+	})
+
+	actions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "actions_total",
+		Help:      "Počet provedených akcí podle repozitáře, druhu a výsledku.",
+	}, []string{"repo", "action", "outcome"})
+
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_requests_total",
+		Help:      "Počet HTTP požadavků na API podle služby a stavového kódu.",
+	}, []string{"service", "code"})
+
+	apiLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "api_request_duration_seconds",
+		Help:      "Doba trvání HTTP požadavků na API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service"})
+
+	rateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_remaining",
+		Help:      "Zbývající počet požadavků do vyčerpání limitu API.",
+	}, []string{"service"})
+)
+
+func init() {
+	prometheus.MustRegister(syncRuns, syncDuration, lastSuccess, actions, apiRequests, apiLatency, rateLimitRemaining)
+}
+
+// Handler vrací HTTP handler pro endpoint /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveRun zaznamená dokončený běh synchronizace. Outcome je "success",
+// "partial" (některé položky selhaly) nebo "error".
+func ObserveRun(kind, outcome string, started time.Time) {
+	syncRuns.WithLabelValues(kind, outcome).Inc()
+	syncDuration.WithLabelValues(kind).Observe(time.Since(started).Seconds())
+	if kind == "full" && outcome == "success" {
+		lastSuccess.SetToCurrentTime()
+	}
+}
+
+// ObserveAction zaznamená výsledek jedné akce. Outcome je "ok", "error"
+// nebo "skipped".
+func ObserveAction(repo, action, outcome string) {
+	actions.WithLabelValues(repo, action, outcome).Inc()
+}
+
+func SetRateLimitRemaining(service string, remaining int) {
+	rateLimitRemaining.WithLabelValues(service).Set(float64(remaining))
+}
+
+// Transport měří počet a dobu HTTP požadavků odeslaných přes base.
+func Transport(service string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		started := time.Now()
+		resp, err := base.RoundTrip(req)
+		apiLatency.WithLabelValues(service).Observe(time.Since(started).Seconds())
+
+		code := "error"
+		if err == nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		apiRequests.WithLabelValues(service, code).Inc()
+		return resp, err
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
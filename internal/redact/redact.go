@@ -0,0 +1,88 @@
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	gosync "sync"
+)
+
+// Placeholder nahrazuje skryté hodnoty.
+const Placeholder = "[REDACTED]"
+
+// minSecretLength chrání před tím, aby příliš krátká hodnota (např. omylem
+// nastavené "x") začala skrývat běžný text.
+const minSecretLength = 6
+
+// patterns zachytí přihlašovací údaje, které nejsou předem známé:
+// autorizační hlavičky, tokeny GitHubu a citlivé parametry v URL či
+// formulářích.
+var patterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*["']?)(?:(?:bearer|token|basic)\s+)?[^\s"',;]+`), "${1}" + Placeholder},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`), "Bearer " + Placeholder},
+	{regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})`), Placeholder},
+	{regexp.MustCompile(`(?i)\b((?:access_)?token|client_secret|secret|password)=[^&\s"']+`), "${1}=" + Placeholder},
+}
+
+var (
+	mu      gosync.RWMutex
+	secrets []string
+)
+
+// Add zaregistruje známá tajemství (tokeny, sdílené klíče webhooků), která
+// se pak skryjí ve všech výstupech. Prázdné a příliš krátké hodnoty
+// ignoruje.
+func Add(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength || contains(secrets, value) {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	// Delší tajemství nahrazujeme dřív, aby po kratším, které je jeho
+	// částí, nezůstal zbytek delšího.
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// String vrátí s, ve kterém jsou známá tajemství a přihlašovací údaje
+// nahrazena zástupným textem.
+func String(s string) string {
+	if s == "" {
+		return s
+	}
+
+	mu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Placeholder)
+	}
+	mu.RUnlock()
+
+	for _, p := range patterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+// Error vrátí skrytý text chyby, pro nil prázdný řetězec.
+func Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	return String(err.Error())
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
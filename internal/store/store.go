@@ -0,0 +1,219 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+)
+
+const fileVersion = 1
+
+type IssueSnapshot struct {
+	Title     string    `json:"title"`
+	State     string    `json:"state"`
+	Labels    []string  `json:"labels,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TaskSnapshot struct {
+	Content     string `json:"content"`
+	Priority    int    `json:"priority"`
+	IsCompleted bool   `json:"is_completed"`
+}
+
+// Link je trvalé propojení GitHub issue s Todoist úkolem spolu se stavem
+// obou stran při poslední úspěšné synchronizaci.
+type Link struct {
+	IssueID     int64         `json:"issue_id"`
+	Repo        string        `json:"repo"`
+	IssueNumber int           `json:"issue_number"`
+	TaskID      string        `json:"task_id"`
+	ProjectID   string        `json:"project_id"`
+	Issue       IssueSnapshot `json:"issue"`
+	Task        TaskSnapshot  `json:"task"`
+	SyncedAt    time.Time     `json:"synced_at"`
+	// TaskDeleted značí, že úkol byl v Todoist smazán. Propojení zůstává,
+	// aby se pro issue nevytvořil nový úkol.
+	TaskDeleted bool `json:"task_deleted,omitempty"`
+	// IssueDeleted značí, že issue při úplném načtení repozitáře chybělo
+	// (bylo smazáno nebo přesunuto mimo synchronizované repozitáře).
+	// Úkol, který na issue dál odkazuje, se pak už nesynchronizuje.
+	IssueDeleted bool `json:"issue_deleted,omitempty"`
+	// Conflict popisuje nevyřešený konflikt čekající na ruční zásah.
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// Ref vrací odkaz na propojené issue ve tvaru owner/repo#číslo.
+func (l *Link) Ref() string {
+	return fmt.Sprintf("%s#%d", l.Repo, l.IssueNumber)
+}
+
+// Cursor je stav přírůstkového načítání issues jednoho repozitáře.
+type Cursor struct {
+	// Since je čas poslední známé změny issue v repozitáři.
+	Since time.Time `json:"since"`
+	// ETag první stránky posledního přírůstkového požadavku.
+	ETag string `json:"etag,omitempty"`
+	// FullScanAt je čas posledního načtení všech issues.
+	FullScanAt time.Time `json:"full_scan_at"`
+}
+
+type fileData struct {
+	Version int                `json:"version"`
+	Links   []*Link            `json:"links"`
+	Cursors map[string]*Cursor `json:"cursors,omitempty"`
+}
+
+type Store struct {
+	path    string
+	mu      sync.Mutex
+	links   map[int64]*Link
+	byTask  map[string]int64
+	cursors map[string]*Cursor
+}
+
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		links:   make(map[int64]*Link),
+		byTask:  make(map[string]int64),
+		cursors: make(map[string]*Cursor),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrStoreRead, path, err)
+	}
+
+	var file fileData
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrStoreParse, path, err)
+	}
+
+	for _, link := range file.Links {
+		s.links[link.IssueID] = link
+		s.byTask[link.TaskID] = link.IssueID
+	}
+	for repo, cursor := range file.Cursors {
+		s.cursors[repo] = cursor
+	}
+
+	return s, nil
+}
+
+func (s *Store) Get(issueID int64) (*Link, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[issueID]
+	if !ok {
+		return nil, false
+	}
+	copied := *link
+	return &copied, true
+}
+
+func (s *Store) GetByTask(taskID string) (*Link, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issueID, ok := s.byTask[taskID]
+	if !ok {
+		return nil, false
+	}
+	copied := *s.links[issueID]
+	return &copied, true
+}
+
+func (s *Store) Put(link *Link) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.links[link.IssueID]; ok && old.TaskID != link.TaskID {
+		delete(s.byTask, old.TaskID)
+	}
+
+	copied := *link
+	s.links[link.IssueID] = &copied
+	s.byTask[link.TaskID] = link.IssueID
+}
+
+func (s *Store) Links() []*Link {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := make([]*Link, 0, len(s.links))
+	for _, link := range s.links {
+		copied := *link
+		links = append(links, &copied)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].IssueID < links[j].IssueID })
+	return links
+}
+
+// Cursor vrací stav přírůstkového načítání repozitáře owner/repo.
+func (s *Store) Cursor(repo string) (Cursor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursor, ok := s.cursors[repo]
+	if !ok {
+		return Cursor{}, false
+	}
+	return *cursor, true
+}
+
+func (s *Store) SetCursor(repo string, cursor Cursor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[repo] = &cursor
+}
+
+// Save zapíše úložiště atomicky přes dočasný soubor, aby přerušený zápis
+// nepoškodil existující data.
+func (s *Store) Save() error {
+	file := fileData{Version: fileVersion, Links: s.Links(), Cursors: make(map[string]*Cursor)}
+	s.mu.Lock()
+	for repo, cursor := range s.cursors {
+		copied := *cursor
+		file.Cursors[repo] = &copied
+	}
+	s.mu.Unlock()
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return i18n.Errorf(i18n.MsgErrStoreSave, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return i18n.Errorf(i18n.MsgErrStoreSave, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return i18n.Errorf(i18n.MsgErrStoreSave, err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return i18n.Errorf(i18n.MsgErrStoreSave, err)
+	}
+
+	return nil
+}
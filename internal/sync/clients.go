@@ -0,0 +1,38 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"github-todoist-sync/internal/github"
+	"github-todoist-sync/internal/todoist"
+)
+
+// IssueTracker jsou operace nad issue, které služba potřebuje. Implementuje
+// ho github.Client, v testech ho lze nahradit implementací v paměti.
+type IssueTracker interface {
+	GetIssues(ctx context.Context, owner, repo string) ([]*github.Issue, error)
+	GetIssuesSince(ctx context.Context, owner, repo string, since time.Time, etag string) (*github.IssueList, error)
+	GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error)
+	UpdateIssueState(ctx context.Context, owner, repo string, number int, state string) error
+	UpdateIssueTitle(ctx context.Context, owner, repo string, number int, title string) error
+	UpdateIssueLabels(ctx context.Context, owner, repo string, number int, remove, add []string) error
+	RateLimit() github.RateLimit
+}
+
+// TaskManager jsou operace nad projekty a úkoly, které služba potřebuje.
+// Implementuje ho todoist.Client.
+type TaskManager interface {
+	GetProjectByName(ctx context.Context, name string) (*todoist.Project, error)
+	CreateProject(ctx context.Context, name string) (*todoist.Project, error)
+	GetSections(ctx context.Context, projectID string) ([]*todoist.Section, error)
+	CreateSection(ctx context.Context, projectID, name string) (*todoist.Section, error)
+	SyncItems(ctx context.Context, syncToken string) (*todoist.SyncResponse, error)
+	GetCompletedTasks(ctx context.Context, projectID string, since time.Time) ([]*todoist.CompletedTask, error)
+	ExecuteCommands(ctx context.Context, commands []todoist.Command) (*todoist.CommandResponse, error)
+}
+
+var (
+	_ IssueTracker = (*github.Client)(nil)
+	_ TaskManager  = (*todoist.Client)(nil)
+)
@@ -0,0 +1,284 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/github"
+	"github-todoist-sync/internal/todoist"
+)
+
+const (
+	testOwner   = "octo"
+	testRepo    = "app"
+	testProject = "App"
+)
+
+// fakeGitHub drží issues jednoho repozitáře v paměti a zaznamenává změny,
+// které na nich služba provedla.
+type fakeGitHub struct {
+	issues map[int]*github.Issue
+	calls  []string
+}
+
+func newFakeGitHub(issues ...*github.Issue) *fakeGitHub {
+	f := &fakeGitHub{issues: make(map[int]*github.Issue)}
+	for _, issue := range issues {
+		f.put(issue)
+	}
+	return f
+}
+
+// put uloží issue jako jeho změnu na GitHubu.
+func (f *fakeGitHub) put(issue *github.Issue) {
+	issue.ID = int64(issue.Number)
+	issue.Owner, issue.Repo = testOwner, testRepo
+	if issue.State == "" {
+		issue.State = "open"
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = time.Now()
+	}
+	f.issues[issue.Number] = issue
+}
+
+func (f *fakeGitHub) issue(number int) *github.Issue {
+	issue := *f.issues[number]
+	issue.Labels = slices.Clone(issue.Labels)
+	return &issue
+}
+
+func (f *fakeGitHub) GetIssues(ctx context.Context, owner, repo string) ([]*github.Issue, error) {
+	return f.list(time.Time{}), nil
+}
+
+// list vrací issues změněná od since.
+func (f *fakeGitHub) list(since time.Time) []*github.Issue {
+	var issues []*github.Issue
+	for number, issue := range f.issues {
+		if !issue.UpdatedAt.Before(since) {
+			issues = append(issues, f.issue(number))
+		}
+	}
+	return issues
+}
+
+func (f *fakeGitHub) GetIssuesSince(ctx context.Context, owner, repo string, since time.Time, etag string) (*github.IssueList, error) {
+	return &github.IssueList{Issues: f.list(since)}, nil
+}
+
+func (f *fakeGitHub) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	if _, ok := f.issues[number]; !ok {
+		return nil, fmt.Errorf("issue %d not found", number)
+	}
+	return f.issue(number), nil
+}
+
+func (f *fakeGitHub) UpdateIssueState(ctx context.Context, owner, repo string, number int, state string) error {
+	f.calls = append(f.calls, fmt.Sprintf("state #%d %s", number, state))
+	f.issues[number].State = state
+	return nil
+}
+
+func (f *fakeGitHub) UpdateIssueTitle(ctx context.Context, owner, repo string, number int, title string) error {
+	f.calls = append(f.calls, fmt.Sprintf("title #%d %q", number, title))
+	f.issues[number].Title = title
+	return nil
+}
+
+func (f *fakeGitHub) UpdateIssueLabels(ctx context.Context, owner, repo string, number int, remove, add []string) error {
+	f.calls = append(f.calls, fmt.Sprintf("labels #%d -%q +%q", number, remove, add))
+	issue := f.issues[number]
+	issue.Labels = append(slices.DeleteFunc(issue.Labels, func(label string) bool {
+		return slices.Contains(remove, label)
+	}), add...)
+	return nil
+}
+
+func (f *fakeGitHub) RateLimit() github.RateLimit {
+	return github.RateLimit{}
+}
+
+// fakeTodoist drží úkoly jednoho projektu v paměti. Sync API napodobuje
+// tokenem, který je pořadovým číslem poslední změny.
+type fakeTodoist struct {
+	items    map[string]*todoist.Item
+	versions map[string]int
+	version  int
+	commands []todoist.Command
+}
+
+func newFakeTodoist() *fakeTodoist {
+	return &fakeTodoist{items: make(map[string]*todoist.Item), versions: make(map[string]int)}
+}
+
+// update změní úkol jako uživatel v Todoist.
+func (f *fakeTodoist) update(id string, change func(*todoist.Item)) {
+	item := f.items[id]
+	change(item)
+	item.UpdatedAt = time.Now()
+	if item.Checked && item.CompletedAt.IsZero() {
+		item.CompletedAt = item.UpdatedAt
+	}
+	f.touch(id)
+}
+
+func (f *fakeTodoist) touch(id string) {
+	f.version++
+	f.versions[id] = f.version
+}
+
+// item vrací úkol propojený s issue number podle odkazu v popisu.
+func (f *fakeTodoist) item(t *testing.T, number int) *todoist.Item {
+	t.Helper()
+	reference := fmt.Sprintf("%s/%s#%d:", testOwner, testRepo, number)
+	for _, item := range f.items {
+		if strings.Contains(item.Description, reference) {
+			return item
+		}
+	}
+	t.Fatalf("no task for issue #%d", number)
+	return nil
+}
+
+// commandTypes vrací typy příkazů odeslaných od indexu from.
+func (f *fakeTodoist) commandTypes(from int) []string {
+	var types []string
+	for _, command := range f.commands[from:] {
+		types = append(types, command.Type)
+	}
+	return types
+}
+
+func (f *fakeTodoist) GetProjectByName(ctx context.Context, name string) (*todoist.Project, error) {
+	return &todoist.Project{ID: "project-1", Name: name}, nil
+}
+
+func (f *fakeTodoist) CreateProject(ctx context.Context, name string) (*todoist.Project, error) {
+	return &todoist.Project{ID: "project-1", Name: name}, nil
+}
+
+func (f *fakeTodoist) GetSections(ctx context.Context, projectID string) ([]*todoist.Section, error) {
+	return nil, nil
+}
+
+func (f *fakeTodoist) CreateSection(ctx context.Context, projectID, name string) (*todoist.Section, error) {
+	return &todoist.Section{ID: "section-1", ProjectID: projectID, Name: name}, nil
+}
+
+func (f *fakeTodoist) SyncItems(ctx context.Context, syncToken string) (*todoist.SyncResponse, error) {
+	resp := &todoist.SyncResponse{SyncToken: strconv.Itoa(f.version)}
+	since, err := strconv.Atoi(syncToken)
+	if syncToken == todoist.FullSyncToken {
+		resp.FullSync = true
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid sync token %q", syncToken)
+	}
+
+	for id, item := range f.items {
+		if resp.FullSync && item.Checked || !resp.FullSync && f.versions[id] <= since {
+			continue
+		}
+		copied := *item
+		resp.Items = append(resp.Items, &copied)
+	}
+	return resp, nil
+}
+
+func (f *fakeTodoist) GetCompletedTasks(ctx context.Context, projectID string, since time.Time) ([]*todoist.CompletedTask, error) {
+	var tasks []*todoist.CompletedTask
+	for id, item := range f.items {
+		if item.Checked && !item.CompletedAt.Before(since) {
+			tasks = append(tasks, &todoist.CompletedTask{
+				ID:          "completed-" + id,
+				TaskID:      id,
+				ProjectID:   item.ProjectID,
+				Content:     item.Content,
+				CompletedAt: item.CompletedAt,
+			})
+		}
+	}
+	return tasks, nil
+}
+
+func (f *fakeTodoist) ExecuteCommands(ctx context.Context, commands []todoist.Command) (*todoist.CommandResponse, error) {
+	f.commands = append(f.commands, commands...)
+	resp := &todoist.CommandResponse{
+		SyncStatus:    make(map[string]json.RawMessage),
+		TempIDMapping: make(map[string]string),
+	}
+
+	for _, command := range commands {
+		var id string
+		switch command.Type {
+		case "item_add":
+			args := command.Args.(*todoist.CreateTaskRequest)
+			id = fmt.Sprintf("task-%d", len(f.items)+1)
+			f.items[id] = &todoist.Item{
+				ID:          id,
+				ProjectID:   args.ProjectID,
+				SectionID:   args.SectionID,
+				Content:     args.Content,
+				Description: args.Description,
+				Priority:    args.Priority,
+				Labels:      args.Labels,
+				AddedAt:     time.Now(),
+			}
+			resp.TempIDMapping[command.TempID] = id
+		case "item_update":
+			args := command.Args.(map[string]interface{})
+			id = args["id"].(string)
+			if content, ok := args["content"].(string); ok {
+				f.items[id].Content = content
+			}
+			if priority, ok := args["priority"].(int); ok {
+				f.items[id].Priority = priority
+			}
+		case "item_close", "item_uncomplete":
+			id = command.Args.(map[string]string)["id"]
+			if created, ok := resp.TempIDMapping[id]; ok {
+				id = created
+			}
+			f.items[id].Checked = command.Type == "item_close"
+			f.items[id].CompletedAt = time.Now()
+		}
+		f.touch(id)
+		resp.SyncStatus[command.UUID] = json.RawMessage(`"ok"`)
+	}
+	resp.SyncToken = strconv.Itoa(f.version)
+	return resp, nil
+}
+
+// newTestService vytvoří službu nad fake klienty s jednou dvojicí
+// repozitář–projekt a výchozími pravidly priorit.
+func newTestService(t *testing.T, gh *fakeGitHub, td *fakeTodoist, directions config.Directions, policy config.ConflictPolicy) *Service {
+	t.Helper()
+	cfg := &config.Config{
+		App: config.AppConfig{
+			StateFile:          t.TempDir() + "/state.json",
+			Concurrency:        1,
+			FullRescanInterval: time.Hour,
+		},
+		Pairs: []config.SyncPair{{
+			Owner:      testOwner,
+			Repo:       testRepo,
+			Project:    testProject,
+			Priorities: config.DefaultPriorityRules(),
+			Directions: directions,
+			Conflicts:  policy,
+		}},
+	}
+
+	service, err := NewServiceWithClients(context.Background(), cfg, gh, td)
+	if err != nil {
+		t.Fatalf("NewServiceWithClients() error = %v", err)
+	}
+	return service
+}
@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"time"
+
+	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/i18n"
+)
+
+type side int
+
+const (
+	sideNone side = iota
+	sideGitHub
+	sideTodoist
+)
+
+// fieldDiff popisuje pole, jehož hodnota se na GitHubu a v Todoist liší,
+// spolu s informací, která strana se od poslední synchronizace změnila.
+type fieldDiff struct {
+	field        string
+	direction    config.Direction
+	issueChanged bool
+	taskChanged  bool
+}
+
+// conflict je souběžná změna stejného pole na obou stranách.
+type conflict struct {
+	field      string
+	resolution string
+	unresolved bool
+}
+
+func (c conflict) String() string {
+	return i18n.T(i18n.MsgConflictDetail, c.field, c.resolution)
+}
+
+// resolveField určí stranu, jejíž hodnota se má použít. Jednosměrná
+// synchronizace vždy přepíše cílovou stranu. Při obousměrné vyhraje strana,
+// která se jako jediná změnila. Pokud se nezměnila žádná (např. po obnovení
+// propojení), vyhrává GitHub. Souběžné změny rozhodne politika konfliktů.
+func resolveField(diff fieldDiff, policy config.ConflictPolicy, issueUpdated, taskUpdated time.Time) (side, *conflict) {
+	toTodoist, toGitHub := diff.direction.ToTodoist(), diff.direction.ToGitHub()
+	switch {
+	case !toTodoist && !toGitHub:
+		return sideNone, nil
+	case !toGitHub:
+		return sideGitHub, nil
+	case !toTodoist:
+		return sideTodoist, nil
+	case diff.taskChanged && !diff.issueChanged:
+		return sideTodoist, nil
+	case !diff.taskChanged:
+		return sideGitHub, nil
+	}
+
+	c := &conflict{field: diff.field}
+	switch policy {
+	case config.ConflictTodoistWins:
+		c.resolution = i18n.T(i18n.MsgConflictTodoist)
+		return sideTodoist, c
+	case config.ConflictManual:
+		c.resolution = i18n.T(i18n.MsgConflictManual)
+		c.unresolved = true
+		return sideNone, c
+	case config.ConflictLastWriterWins:
+		switch {
+		case taskUpdated.IsZero():
+			c.resolution = i18n.T(i18n.MsgConflictNoTaskTime)
+		case taskUpdated.After(issueUpdated):
+			c.resolution = i18n.T(i18n.MsgConflictNewerTask,
+				taskUpdated.Format(time.RFC3339), issueUpdated.Format(time.RFC3339))
+			return sideTodoist, c
+		default:
+			c.resolution = i18n.T(i18n.MsgConflictNewerIssue,
+				issueUpdated.Format(time.RFC3339), taskUpdated.Format(time.RFC3339))
+		}
+		return sideGitHub, c
+	default:
+		c.resolution = i18n.T(i18n.MsgConflictGitHub)
+		return sideGitHub, c
+	}
+}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/github"
+	"github-todoist-sync/internal/todoist"
+)
+
+func TestResolveField(t *testing.T) {
+	older := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		direction    config.Direction
+		issueChanged bool
+		taskChanged  bool
+		policy       config.ConflictPolicy
+		issueUpdated time.Time
+		taskUpdated  time.Time
+		want         side
+		wantConflict bool
+		unresolved   bool
+	}{
+		{"direction none", config.DirectionNone, true, true, config.ConflictGitHubWins, older, newer, sideNone, false, false},
+		{"to Todoist only", config.DirectionToTodoist, false, true, config.ConflictTodoistWins, older, newer, sideGitHub, false, false},
+		{"to GitHub only", config.DirectionToGitHub, true, false, config.ConflictGitHubWins, newer, older, sideTodoist, false, false},
+		{"only task changed", config.DirectionBoth, false, true, config.ConflictGitHubWins, older, newer, sideTodoist, false, false},
+		{"only issue changed", config.DirectionBoth, true, false, config.ConflictTodoistWins, older, newer, sideGitHub, false, false},
+		{"nothing changed", config.DirectionBoth, false, false, config.ConflictTodoistWins, older, newer, sideGitHub, false, false},
+		{"github-wins", config.DirectionBoth, true, true, config.ConflictGitHubWins, older, newer, sideGitHub, true, false},
+		{"todoist-wins", config.DirectionBoth, true, true, config.ConflictTodoistWins, newer, older, sideTodoist, true, false},
+		{"manual", config.DirectionBoth, true, true, config.ConflictManual, older, newer, sideNone, true, true},
+		{"last-writer-wins, task newer", config.DirectionBoth, true, true, config.ConflictLastWriterWins, older, newer, sideTodoist, true, false},
+		{"last-writer-wins, issue newer", config.DirectionBoth, true, true, config.ConflictLastWriterWins, newer, older, sideGitHub, true, false},
+		{"last-writer-wins, same time", config.DirectionBoth, true, true, config.ConflictLastWriterWins, older, older, sideGitHub, true, false},
+		{"last-writer-wins, no task time", config.DirectionBoth, true, true, config.ConflictLastWriterWins, older, time.Time{}, sideGitHub, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := fieldDiff{"title", tt.direction, tt.issueChanged, tt.taskChanged}
+			got, c := resolveField(diff, tt.policy, tt.issueUpdated, tt.taskUpdated)
+			if got != tt.want {
+				t.Errorf("resolveField() side = %d, want %d", got, tt.want)
+			}
+			if (c != nil) != tt.wantConflict {
+				t.Fatalf("resolveField() conflict = %v, want conflict %v", c, tt.wantConflict)
+			}
+			if c != nil && (c.unresolved != tt.unresolved || c.field != "title" || c.resolution == "") {
+				t.Errorf("resolveField() conflict = %+v, want field title, unresolved %v", *c, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestFullSyncReportsManualConflictOnce(t *testing.T) {
+	gh := newFakeGitHub(&github.Issue{Number: 1, Title: "Fix login"})
+	td := newFakeTodoist()
+	s := newTestService(t, gh, td, bothDirections, config.ConflictManual)
+	ctx := context.Background()
+
+	if _, err := s.FullSync(ctx); err != nil {
+		t.Fatalf("FullSync() error = %v", err)
+	}
+
+	// Název se změní na obou stranách.
+	issue := gh.issue(1)
+	issue.Title = "Fix login on GitHub"
+	issue.UpdatedAt = time.Now().Add(time.Second)
+	gh.put(issue)
+	td.update(td.item(t, 1).ID, func(item *todoist.Item) {
+		item.Content = "Fix login in Todoist"
+	})
+
+	sent := len(td.commands)
+	result, err := s.FullSync(ctx)
+	if err != nil {
+		t.Fatalf("FullSync() error = %v", err)
+	}
+	var conflicts int
+	for _, skipped := range result.Skipped {
+		if strings.HasPrefix(skipped, "octo/app#1") {
+			conflicts++
+		}
+	}
+	if conflicts != 1 {
+		t.Errorf("FullSync() skipped = %q, want the conflict once", result.Skipped)
+	}
+	if len(gh.calls) > 0 || len(td.commands) > sent {
+		t.Errorf("FullSync() changed GitHub %q and Todoist %q, want no changes", gh.calls, td.commandTypes(sent))
+	}
+
+	link, ok := s.store.Get(1)
+	if !ok || link.Conflict == "" {
+		t.Error("link has no recorded conflict")
+	}
+}
@@ -0,0 +1,260 @@
+package sync
+
+import (
+	"context"
+	gosync "sync"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/store"
+	"github-todoist-sync/internal/todoist"
+)
+
+// ErrSkipped označuje akci, která se neprovedla, protože selhala
+// předchozí akce téhož issue.
+var ErrSkipped = i18n.Errorf(i18n.MsgErrSkipped)
+
+type ActionResult struct {
+	Action Action
+	Err    error
+}
+
+// Executor provádí naplánované akce a hlásí výsledek každé z nich.
+// Po provedení akcí jednoho issue uloží jeho propojení do úložiště.
+type Executor interface {
+	Execute(ctx context.Context, plan *Plan) []ActionResult
+}
+
+// apiExecutor provádí akce přes API. Změny v Todoist posílá dávkově přes
+// Sync API, akce na GitHubu provádí jednotlivě. Issues a dávky zpracovává
+// souběžně nejvýše concurrency workery. Akce jednoho issue provádí vždy
+// jeden worker v pořadí plánu. Limity API hlídají klienti, při blížícím se
+// vyčerpání limitu počkají všechny workery.
+type apiExecutor struct {
+	githubClient  IssueTracker
+	todoistClient TaskManager
+	store         *store.Store
+	concurrency   int
+	progress      *progressTracker
+}
+
+// batchedIssue jsou příkazy jednoho issue čekající na odeslání v dávce.
+// Příkazy jednoho issue jsou vždy ve stejné dávce, aby se mohly odkazovat
+// na temp_id právě zakládaného úkolu.
+type batchedIssue struct {
+	ip       *issuePlan
+	results  *[]ActionResult
+	commands []todoist.Command
+}
+
+func (e *apiExecutor) Execute(ctx context.Context, plan *Plan) []ActionResult {
+	perIssue := make([][]ActionResult, len(plan.issues))
+	var jobs []func()
+	var batch []*batchedIssue
+	var batchSize int
+
+	queueBatch := func() {
+		if len(batch) > 0 {
+			queued := batch
+			jobs = append(jobs, func() { e.flush(ctx, queued) })
+		}
+		batch, batchSize = nil, 0
+	}
+
+	for i, ip := range plan.issues {
+		if !todoistOnly(ip) {
+			ip, results := ip, &perIssue[i]
+			jobs = append(jobs, func() { *results = e.executeIssue(ctx, ip) })
+			continue
+		}
+
+		queued := &batchedIssue{ip: ip, results: &perIssue[i], commands: commandsFor(ip)}
+		if batchSize+len(queued.commands) > todoist.MaxBatchCommands {
+			queueBatch()
+		}
+		batch = append(batch, queued)
+		batchSize += len(queued.commands)
+	}
+	queueBatch()
+
+	runPool(e.concurrency, jobs)
+
+	var results []ActionResult
+	for _, issueResults := range perIssue {
+		results = append(results, issueResults...)
+	}
+	return results
+}
+
+// runPool spustí úlohy nejvýše po limit souběžně a počká na dokončení.
+func runPool(limit int, jobs []func()) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var wg gosync.WaitGroup
+	slots := make(chan struct{}, limit)
+	for _, job := range jobs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(job func()) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			job()
+		}(job)
+	}
+	wg.Wait()
+}
+
+// executeIssue provede akce issue jednu po druhé a po první chybě zbytek
+// přeskočí.
+func (e *apiExecutor) executeIssue(ctx context.Context, ip *issuePlan) []ActionResult {
+	var results []ActionResult
+	link := ip.link
+	for i, action := range ip.actions {
+		if action.TaskID == "" {
+			action.TaskID = link.TaskID
+		}
+
+		err := e.execute(ctx, action)
+		results = append(results, ActionResult{Action: *action, Err: err})
+		e.progress.actionDone(1)
+		if err != nil {
+			for _, skipped := range ip.actions[i+1:] {
+				results = append(results, ActionResult{Action: *skipped, Err: ErrSkipped})
+			}
+			e.progress.actionDone(len(ip.actions[i+1:]))
+			break
+		}
+		action.applyTo(&link)
+	}
+
+	if link.TaskID != "" {
+		e.store.Put(&link)
+	}
+	return results
+}
+
+func (e *apiExecutor) execute(ctx context.Context, a *Action) error {
+	switch a.Type {
+	case ActionCloseIssue:
+		return e.githubClient.UpdateIssueState(ctx, a.owner, a.name, a.Issue, "closed")
+	case ActionReopenIssue:
+		return e.githubClient.UpdateIssueState(ctx, a.owner, a.name, a.Issue, "open")
+	case ActionUpdateIssueTitle:
+		return e.githubClient.UpdateIssueTitle(ctx, a.owner, a.name, a.Issue, a.value)
+	case ActionUpdateIssueLabel:
+		return e.githubClient.UpdateIssueLabels(ctx, a.owner, a.name, a.Issue, a.removeLabels, a.addLabels)
+	}
+	return i18n.Errorf(i18n.MsgErrUnknownAction, a.Type)
+}
+
+// flush odešle dávku a výsledky příkazů přiřadí zpět akcím jednotlivých
+// issues.
+func (e *apiExecutor) flush(ctx context.Context, batch []*batchedIssue) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var commands []todoist.Command
+	for _, queued := range batch {
+		commands = append(commands, queued.commands...)
+	}
+	resp, batchErr := e.todoistClient.ExecuteCommands(ctx, commands)
+
+	for _, queued := range batch {
+		link := queued.ip.link
+		for i, action := range queued.ip.actions {
+			command := queued.commands[i]
+			err := batchErr
+			if err == nil {
+				err = resp.Err(command.UUID)
+			}
+
+			if err == nil && action.Type == ActionCreateTask {
+				link.TaskID = resp.TempIDMapping[command.TempID]
+				link.Task = taskSnapshot(&todoist.Task{Content: action.create.Content, Priority: action.create.Priority})
+			}
+			if action.TaskID == "" {
+				action.TaskID = link.TaskID
+			}
+
+			*queued.results = append(*queued.results, ActionResult{Action: *action, Err: err})
+			e.progress.actionDone(1)
+			if err == nil {
+				action.applyTo(&link)
+			}
+		}
+
+		if link.TaskID != "" {
+			e.store.Put(&link)
+		}
+	}
+}
+
+// todoistOnly říká, zda lze všechny akce issue poslat dávkou do Todoist.
+func todoistOnly(ip *issuePlan) bool {
+	for _, action := range ip.actions {
+		switch action.Type {
+		case ActionCreateTask, ActionUpdateTaskFields, ActionCloseTask, ActionReopenTask:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// commandsFor převede akce issue na příkazy Sync API. Akce následující
+// po založení úkolu se na něj odkazují přes temp_id.
+func commandsFor(ip *issuePlan) []todoist.Command {
+	var commands []todoist.Command
+	taskID := ip.link.TaskID
+	for _, action := range ip.actions {
+		if action.TaskID != "" {
+			taskID = action.TaskID
+		}
+
+		var command todoist.Command
+		switch action.Type {
+		case ActionCreateTask:
+			command = todoist.AddItemCommand(action.create)
+			taskID = command.TempID
+		case ActionUpdateTaskFields:
+			command = todoist.UpdateItemCommand(taskID, action.updates)
+		case ActionCloseTask:
+			command = todoist.CloseItemCommand(taskID)
+		case ActionReopenTask:
+			command = todoist.UncompleteItemCommand(taskID)
+		}
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// dryRunExecutor akce jen zaznamená. Stav propojení aktualizuje v paměti,
+// aby navazující plán počítal s již naplánovanými změnami, úložiště se ale
+// neukládá.
+type dryRunExecutor struct {
+	store    *store.Store
+	record   func(Action)
+	progress *progressTracker
+}
+
+func (e *dryRunExecutor) Execute(ctx context.Context, plan *Plan) []ActionResult {
+	var results []ActionResult
+
+	for _, ip := range plan.issues {
+		link := ip.link
+		for _, action := range ip.actions {
+			e.record(*action)
+			results = append(results, ActionResult{Action: *action})
+			e.progress.actionDone(1)
+			action.applyTo(&link)
+		}
+
+		if link.TaskID != "" {
+			e.store.Put(&link)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,347 @@
+package sync
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/github"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/store"
+	"github-todoist-sync/internal/todoist"
+)
+
+type ActionType string
+
+const (
+	ActionCreateProject    ActionType = "create_project"
+	ActionCreateSection    ActionType = "create_section"
+	ActionCreateTask       ActionType = "create_task"
+	ActionUpdateTaskFields ActionType = "update_task"
+	ActionCloseTask        ActionType = "close_task"
+	ActionReopenTask       ActionType = "reopen_task"
+	ActionCloseIssue       ActionType = "close_issue"
+	ActionReopenIssue      ActionType = "reopen_issue"
+	ActionUpdateIssueTitle ActionType = "update_issue_title"
+	ActionUpdateIssueLabel ActionType = "update_issue_labels"
+)
+
+// Action je jedna naplánovaná změna v GitHubu nebo v Todoist. Exportovaná
+// pole popisují změnu pro výpis, neexportovaná nesou data pro provedení.
+type Action struct {
+	Type   ActionType `json:"action"`
+	Repo   string     `json:"repo,omitempty"`
+	Issue  int        `json:"issue,omitempty"`
+	TaskID string     `json:"task_id,omitempty"`
+	Detail string     `json:"detail,omitempty"`
+
+	owner   string
+	name    string
+	create  *todoist.CreateTaskRequest
+	updates map[string]interface{}
+	value   string
+	// removeLabels a addLabels mění prioritní štítky issue, labels jsou
+	// štítky po změně a priority priorita úkolu, kterou vyjadřují.
+	removeLabels []string
+	addLabels    []string
+	labels       []string
+	priority     int
+}
+
+// applyTo promítne úspěšně provedenou akci do uloženého stavu propojení.
+// Po provedení akce mají obě strany stejnou hodnotu pole.
+func (a *Action) applyTo(link *store.Link) {
+	switch a.Type {
+	case ActionUpdateTaskFields:
+		if content, ok := a.updates["content"].(string); ok {
+			link.Task.Content = content
+			link.Issue.Title = content
+		}
+		if priority, ok := a.updates["priority"].(int); ok {
+			link.Task.Priority = priority
+		}
+	case ActionCloseTask, ActionCloseIssue:
+		link.Task.IsCompleted = true
+		link.Issue.State = "closed"
+	case ActionReopenTask, ActionReopenIssue:
+		link.Task.IsCompleted = false
+		link.Issue.State = "open"
+	case ActionUpdateIssueTitle:
+		link.Task.Content = a.value
+		link.Issue.Title = a.value
+	case ActionUpdateIssueLabel:
+		link.Task.Priority = a.priority
+		link.Issue.Labels = a.labels
+	}
+}
+
+// issuePlan sdružuje akce jedné dvojice issue–úkol. Akce se provádějí
+// v pořadí a po první chybě se zbytek přeskočí, protože na sobě mohou
+// záviset (např. uzavření právě vytvořeného úkolu).
+type issuePlan struct {
+	link      store.Link
+	actions   []*Action
+	conflicts []conflict
+}
+
+// resolve rozhodne rozdíl v jednom poli a zaznamená případný konflikt.
+func (ip *issuePlan) resolve(pair config.SyncPair, diff fieldDiff, issue *github.Issue, task *todoist.Task) side {
+	winner, c := resolveField(diff, pair.Conflicts, issue.UpdatedAt, task.UpdatedAt)
+	if c != nil {
+		ip.conflicts = append(ip.conflicts, *c)
+	}
+	return winner
+}
+
+// finish uloží do propojení popis konfliktů, které čekají na ruční
+// vyřešení, nebo ho vymaže, pokud žádné nejsou.
+func (ip *issuePlan) finish() *issuePlan {
+	var unresolved []string
+	for _, c := range ip.conflicts {
+		if c.unresolved {
+			unresolved = append(unresolved, c.String())
+		}
+	}
+	ip.link.Conflict = strings.Join(unresolved, "; ")
+	return ip
+}
+
+// Plan je seznam naplánovaných změn připravený k předání exekutoru.
+type Plan struct {
+	issues  []*issuePlan
+	skipped []string
+}
+
+func (p *Plan) add(ip *issuePlan) {
+	p.issues = append(p.issues, ip)
+}
+
+func (p *Plan) merge(other *Plan) {
+	p.issues = append(p.issues, other.issues...)
+	p.skipped = append(p.skipped, other.skipped...)
+}
+
+// skip zaznamená položku, pro kterou se záměrně nic neplánuje.
+func (p *Plan) skip(item, reason string) {
+	p.skipped = append(p.skipped, item+": "+reason)
+}
+
+func newIssueAction(actionType ActionType, issue *github.Issue, taskID, detail string) *Action {
+	return &Action{
+		Type:   actionType,
+		Repo:   issue.FullName(),
+		Issue:  issue.Number,
+		TaskID: taskID,
+		Detail: detail,
+		owner:  issue.Owner,
+		name:   issue.Repo,
+	}
+}
+
+// planCreateTask naplánuje založení úkolu pro dosud nepropojené issue.
+func planCreateTask(repo *repoSync, issue *github.Issue) *issuePlan {
+	create := newIssueAction(ActionCreateTask, issue, "", issue.Title)
+	create.create = &todoist.CreateTaskRequest{
+		Content:     issue.Title,
+		Description: todoist.FormatGitHubReference(issue.FullName(), issue.Number, issue.HTMLURL),
+		ProjectID:   repo.project.ID,
+		SectionID:   repo.sectionID,
+		Priority:    repo.pair.Priorities.Priority(issue.Labels),
+		Labels:      convertLabels(issue.Labels),
+	}
+
+	ip := &issuePlan{
+		link:    newLink(issue, repo.project.ID, "", store.TaskSnapshot{}),
+		actions: []*Action{create},
+	}
+	if issue.State == "closed" {
+		ip.actions = append(ip.actions, newIssueAction(ActionCloseTask, issue, "", ""))
+	}
+	return ip
+}
+
+// planToTodoist porovná issue s propojeným úkolem a naplánuje změny
+// v Todoist podle nastavených směrů synchronizace a politiky konfliktů.
+// Hodnoty, které zůstávají na opačném směru nebo v nevyřešeném konfliktu,
+// ponechá v uloženém stavu beze změny.
+func planToTodoist(pair config.SyncPair, link *store.Link, task *todoist.Task, issue *github.Issue) *issuePlan {
+	directions := pair.Directions
+	ip := &issuePlan{link: newLink(issue, link.ProjectID, task.ID, link.Task)}
+	synced := &ip.link.Issue
+	snapshot := &ip.link.Task
+	updates := make(map[string]interface{})
+
+	if task.Content == issue.Title {
+		snapshot.Content = task.Content
+	} else {
+		diff := fieldDiff{"title", directions.Title, issue.Title != link.Issue.Title, task.Content != link.Task.Content}
+		switch ip.resolve(pair, diff, issue, task) {
+		case sideGitHub:
+			updates["content"] = issue.Title
+		case sideNone:
+			synced.Title = link.Issue.Title
+		}
+	}
+
+	newPriority := pair.Priorities.Priority(issue.Labels)
+	if task.Priority == newPriority {
+		snapshot.Priority = task.Priority
+	} else {
+		diff := priorityDiff(pair, link, task, newPriority)
+		switch ip.resolve(pair, diff, issue, task) {
+		case sideGitHub:
+			updates["priority"] = newPriority
+		case sideNone:
+			synced.Labels = link.Issue.Labels
+		}
+	}
+
+	if len(updates) > 0 {
+		update := newIssueAction(ActionUpdateTaskFields, issue, task.ID, describeUpdates(task, updates))
+		update.updates = updates
+		ip.actions = append(ip.actions, update)
+	}
+
+	shouldBeClosed := issue.State == "closed"
+	if task.IsCompleted == shouldBeClosed {
+		snapshot.IsCompleted = task.IsCompleted
+	} else {
+		diff := fieldDiff{"state", directions.State, issue.State != link.Issue.State, task.IsCompleted != link.Task.IsCompleted}
+		switch ip.resolve(pair, diff, issue, task) {
+		case sideGitHub:
+			actionType := ActionReopenTask
+			if shouldBeClosed {
+				actionType = ActionCloseTask
+			}
+			ip.actions = append(ip.actions, newIssueAction(actionType, issue, task.ID, ""))
+		case sideNone:
+			synced.State = link.Issue.State
+		}
+	}
+
+	return ip.finish()
+}
+
+// planToGitHub porovná úkol s propojeným issue a naplánuje změny na
+// GitHubu. Hodnoty, které má propsat opačný směr, ponechá v uloženém stavu
+// issue beze změny, aby je SyncFromGitHub rozpoznal jako změněné.
+func planToGitHub(pair config.SyncPair, link *store.Link, task *todoist.Task, issue *github.Issue) *issuePlan {
+	directions := pair.Directions
+	ip := &issuePlan{link: newLink(issue, link.ProjectID, task.ID, link.Task)}
+	synced := &ip.link.Issue
+	snapshot := &ip.link.Task
+
+	isClosed := issue.State == "closed"
+	if task.IsCompleted == isClosed {
+		snapshot.IsCompleted = task.IsCompleted
+	} else {
+		diff := fieldDiff{"state", directions.State, issue.State != link.Issue.State, task.IsCompleted != link.Task.IsCompleted}
+		if ip.resolve(pair, diff, issue, task) == sideTodoist {
+			actionType := ActionReopenIssue
+			if task.IsCompleted {
+				actionType = ActionCloseIssue
+			}
+			ip.actions = append(ip.actions, newIssueAction(actionType, issue, task.ID, ""))
+		} else {
+			synced.State = link.Issue.State
+		}
+	}
+
+	if task.Content == issue.Title {
+		snapshot.Content = task.Content
+	} else {
+		diff := fieldDiff{"title", directions.Title, issue.Title != link.Issue.Title, task.Content != link.Task.Content}
+		if ip.resolve(pair, diff, issue, task) == sideTodoist {
+			detail := fmt.Sprintf("%q → %q", issue.Title, task.Content)
+			update := newIssueAction(ActionUpdateIssueTitle, issue, task.ID, detail)
+			update.value = task.Content
+			ip.actions = append(ip.actions, update)
+		} else {
+			synced.Title = link.Issue.Title
+		}
+	}
+
+	if priority := pair.Priorities.Priority(issue.Labels); task.Priority == priority {
+		snapshot.Priority = task.Priority
+	} else if ip.resolve(pair, priorityDiff(pair, link, task, priority), issue, task) == sideTodoist {
+		remove, add, ok := pair.Priorities.Relabel(issue.Labels, task.Priority)
+		if ok {
+			labels := relabeled(issue.Labels, remove, add)
+			detail := i18n.T(i18n.MsgDetailLabels, priority, task.Priority, issue.Labels, labels)
+			update := newIssueAction(ActionUpdateIssueLabel, issue, task.ID, detail)
+			update.removeLabels = remove
+			if add != "" {
+				update.addLabels = []string{add}
+			}
+			update.labels = labels
+			update.priority = task.Priority
+			ip.actions = append(ip.actions, update)
+		} else {
+			// Prioritu nelze vyjádřit žádným štítkem, změna zůstane
+			// nepromítnutá.
+			synced.Labels = link.Issue.Labels
+		}
+	} else {
+		synced.Labels = link.Issue.Labels
+	}
+
+	return ip.finish()
+}
+
+// relabeled vrací štítky issue po odebrání remove a přidání add.
+func relabeled(labels, remove []string, add string) []string {
+	result := []string{}
+	for _, label := range labels {
+		if !slices.Contains(remove, label) {
+			result = append(result, label)
+		}
+	}
+	if add != "" {
+		result = append(result, add)
+	}
+	return result
+}
+
+// priorityDiff porovná prioritu úkolu s prioritou podle štítků issue.
+// Změnu na GitHubu pozná podle toho, zda štítky znamenají jinou prioritu
+// než při poslední synchronizaci.
+func priorityDiff(pair config.SyncPair, link *store.Link, task *todoist.Task, issuePriority int) fieldDiff {
+	return fieldDiff{
+		field:        "priority",
+		direction:    pair.Directions.Priority,
+		issueChanged: issuePriority != pair.Priorities.Priority(link.Issue.Labels),
+		taskChanged:  task.Priority != link.Task.Priority,
+	}
+}
+
+// pendingToGitHub říká, zda úkol nese změnu, kterou je třeba promítnout do
+// GitHubu. Porovnává úkol s posledním známým stavem obou stran, takže
+// nezměněné úkoly nevyžadují načtení issue.
+func pendingToGitHub(pair config.SyncPair, link *store.Link, task *todoist.Task) bool {
+	directions := pair.Directions
+	if directions.State.ToGitHub() &&
+		(task.IsCompleted != link.Task.IsCompleted || task.IsCompleted != (link.Issue.State == "closed")) {
+		return true
+	}
+	if directions.Title.ToGitHub() &&
+		(task.Content != link.Task.Content || task.Content != link.Issue.Title) {
+		return true
+	}
+	if directions.Priority.ToGitHub() &&
+		(task.Priority != link.Task.Priority || task.Priority != pair.Priorities.Priority(link.Issue.Labels)) {
+		return true
+	}
+	return false
+}
+
+func describeUpdates(task *todoist.Task, updates map[string]interface{}) string {
+	var parts []string
+	if content, ok := updates["content"]; ok {
+		parts = append(parts, i18n.T(i18n.MsgDetailTitle, task.Content, content))
+	}
+	if priority, ok := updates["priority"]; ok {
+		parts = append(parts, i18n.T(i18n.MsgDetailPriority, task.Priority, priority))
+	}
+	return strings.Join(parts, ", ")
+}
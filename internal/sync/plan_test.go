@@ -0,0 +1,171 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github-todoist-sync/internal/config"
+	"github-todoist-sync/internal/github"
+	"github-todoist-sync/internal/store"
+	"github-todoist-sync/internal/todoist"
+)
+
+var bothDirections = config.Directions{
+	State:    config.DirectionBoth,
+	Title:    config.DirectionBoth,
+	Priority: config.DirectionBoth,
+}
+
+func TestSyncFromGitHubPlansTodoistChanges(t *testing.T) {
+	gh := newFakeGitHub(
+		&github.Issue{Number: 1, Title: "Fix login", Labels: []string{"bug", "high"}},
+		&github.Issue{Number: 2, Title: "Old crash", State: "closed"},
+	)
+	td := newFakeTodoist()
+	directions := config.Directions{
+		State:    config.DirectionBoth,
+		Title:    config.DirectionToTodoist,
+		Priority: config.DirectionToTodoist,
+	}
+	s := newTestService(t, gh, td, directions, config.ConflictGitHubWins)
+	ctx := context.Background()
+
+	result, err := s.SyncFromGitHub(ctx)
+	if err != nil {
+		t.Fatalf("SyncFromGitHub() error = %v", err)
+	}
+	if result.Created != 2 || result.Failed() {
+		t.Errorf("SyncFromGitHub() = %v, want 2 created", result)
+	}
+	types := td.commandTypes(0)
+	slices.Sort(types)
+	if want := []string{"item_add", "item_add", "item_close"}; !slices.Equal(types, want) {
+		t.Errorf("commands = %q, want %q", types, want)
+	}
+	if item := td.item(t, 1); item.Content != "Fix login" || item.Priority != 3 || item.Checked {
+		t.Errorf("task for #1 = %q, priority %d, checked %v, want open \"Fix login\" with priority 3",
+			item.Content, item.Priority, item.Checked)
+	}
+	if item := td.item(t, 2); !item.Checked {
+		t.Error("task for #2 is open, want closed")
+	}
+
+	// Změna názvu a prioritního štítku na GitHubu se propíše jedním
+	// příkazem.
+	issue := gh.issue(1)
+	issue.Title = "Fix login page"
+	issue.Labels = []string{"bug", "urgent"}
+	issue.UpdatedAt = time.Now().Add(time.Second)
+	gh.put(issue)
+
+	sent := len(td.commands)
+	if _, err := s.SyncFromGitHub(ctx); err != nil {
+		t.Fatalf("SyncFromGitHub() error = %v", err)
+	}
+	if types, want := td.commandTypes(sent), []string{"item_update"}; !slices.Equal(types, want) {
+		t.Errorf("commands = %q, want %q", types, want)
+	}
+	if item := td.item(t, 1); item.Content != "Fix login page" || item.Priority != 4 {
+		t.Errorf("task for #1 = %q, priority %d, want \"Fix login page\" with priority 4", item.Content, item.Priority)
+	}
+	if len(gh.calls) > 0 {
+		t.Errorf("GitHub calls = %q, want none", gh.calls)
+	}
+}
+
+func TestSyncToGitHubPlansIssueChanges(t *testing.T) {
+	gh := newFakeGitHub(&github.Issue{Number: 1, Title: "Fix login", Labels: []string{"bug", "high"}})
+	td := newFakeTodoist()
+	s := newTestService(t, gh, td, bothDirections, config.ConflictGitHubWins)
+	ctx := context.Background()
+
+	if _, err := s.SyncFromGitHub(ctx); err != nil {
+		t.Fatalf("SyncFromGitHub() error = %v", err)
+	}
+	td.update(td.item(t, 1).ID, func(item *todoist.Item) {
+		item.Content = "Fix login page"
+		item.Priority = 4
+		item.Checked = true
+	})
+
+	sent := len(td.commands)
+	if _, err := s.SyncToGitHub(ctx); err != nil {
+		t.Fatalf("SyncToGitHub() error = %v", err)
+	}
+	want := []string{`state #1 closed`, `title #1 "Fix login page"`, `labels #1 -["high"] +["urgent"]`}
+	if !slices.Equal(gh.calls, want) {
+		t.Errorf("GitHub calls = %q, want %q", gh.calls, want)
+	}
+	if labels := gh.issue(1).Labels; !slices.Equal(labels, []string{"bug", "urgent"}) {
+		t.Errorf("issue labels = %q, want [bug urgent]", labels)
+	}
+	if types := td.commandTypes(sent); len(types) > 0 {
+		t.Errorf("commands = %q, want none", types)
+	}
+
+	// Propsaný úkol už nic dalšího nevyžaduje.
+	calls := len(gh.calls)
+	if _, err := s.SyncToGitHub(ctx); err != nil {
+		t.Fatalf("SyncToGitHub() error = %v", err)
+	}
+	if again := gh.calls[calls:]; len(again) > 0 {
+		t.Errorf("second SyncToGitHub() calls = %q, want none", again)
+	}
+}
+
+func TestPlanToGitHubPriority(t *testing.T) {
+	wildcard, err := config.NewPriorityRule("sev*", 4, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	high, err := config.NewPriorityRule("high", 3, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		rules      config.PriorityRules
+		priority   int
+		wantRemove []string
+		wantAdd    []string
+		wantLabels []string
+	}{
+		{"raise", config.DefaultPriorityRules(), 4, []string{"high"}, []string{"urgent"}, []string{"bug", "urgent"}},
+		{"lower to default", config.DefaultPriorityRules(), 1, []string{"high"}, nil, []string{"bug"}},
+		{"unchanged", config.DefaultPriorityRules(), 3, nil, nil, nil},
+		{"no label for priority", config.PriorityRules{wildcard, high}, 4, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := config.SyncPair{Owner: testOwner, Repo: testRepo, Priorities: tt.rules, Directions: bothDirections}
+			issue := &github.Issue{ID: 1, Owner: testOwner, Repo: testRepo, Number: 1, Title: "Fix login", State: "open", Labels: []string{"bug", "high"}}
+			link := newLink(issue, "project-1", "task-1", store.TaskSnapshot{Content: "Fix login", Priority: 3})
+			task := &todoist.Task{ID: "task-1", Content: "Fix login", Priority: tt.priority}
+
+			ip := planToGitHub(pair, &link, task, issue)
+			if tt.wantLabels == nil {
+				if len(ip.actions) > 0 {
+					t.Fatalf("planToGitHub() planned %d actions, want none", len(ip.actions))
+				}
+				if !slices.Equal(ip.link.Issue.Labels, link.Issue.Labels) {
+					t.Errorf("synced labels = %q, want %q", ip.link.Issue.Labels, link.Issue.Labels)
+				}
+				return
+			}
+
+			if len(ip.actions) != 1 || ip.actions[0].Type != ActionUpdateIssueLabel {
+				t.Fatalf("planToGitHub() actions = %v, want one %s", ip.actions, ActionUpdateIssueLabel)
+			}
+			a := ip.actions[0]
+			if !slices.Equal(a.removeLabels, tt.wantRemove) || !slices.Equal(a.addLabels, tt.wantAdd) {
+				t.Errorf("labels change = -%q +%q, want -%q +%q", a.removeLabels, a.addLabels, tt.wantRemove, tt.wantAdd)
+			}
+			if !slices.Equal(a.labels, tt.wantLabels) || a.priority != tt.priority {
+				t.Errorf("labels after change = %q (priority %d), want %q (priority %d)", a.labels, a.priority, tt.wantLabels, tt.priority)
+			}
+		})
+	}
+}
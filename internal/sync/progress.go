@@ -0,0 +1,61 @@
+package sync
+
+import (
+	gosync "sync"
+)
+
+// Phase je směr, kterým právě probíhá synchronizace.
+type Phase string
+
+const (
+	PhaseToTodoist Phase = "to_todoist"
+	PhaseToGitHub  Phase = "to_github"
+)
+
+// Progress je průběh právě probíhající synchronizace: směr, počet
+// zpracovaných repozitářů a provedených akcí z naplánovaných.
+type Progress struct {
+	Phase          Phase `json:"phase"`
+	ReposDone      int   `json:"repos_done"`
+	ReposTotal     int   `json:"repos_total"`
+	ActionsDone    int   `json:"actions_done"`
+	ActionsPlanned int   `json:"actions_planned"`
+}
+
+// progressTracker průběh sdílí mezi službou, workery executoru a daemonem,
+// který ho čte pro stav.
+type progressTracker struct {
+	mu       gosync.Mutex
+	progress Progress
+}
+
+// start začne nový směr synchronizace a vynuluje počty.
+func (t *progressTracker) start(phase Phase, repos int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = Progress{Phase: phase, ReposTotal: repos}
+}
+
+func (t *progressTracker) repoDone(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress.ReposDone += n
+}
+
+func (t *progressTracker) planned(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress.ActionsPlanned += n
+}
+
+func (t *progressTracker) actionDone(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress.ActionsDone += n
+}
+
+func (t *progressTracker) snapshot() Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
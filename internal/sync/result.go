@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github-todoist-sync/internal/i18n"
+)
+
+// ItemError je chyba synchronizace jedné položky (issue nebo úkolu).
+type ItemError struct {
+	Item   string
+	Action ActionType
+	Err    error
+}
+
+func (e ItemError) Error() string {
+	if e.Action == "" {
+		return fmt.Sprintf("%s: %v", e.Item, e.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", e.Item, e.Action, e.Err)
+}
+
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// SyncResult shrnuje jeden běh synchronizace. Chyby jednotlivých položek
+// běh nepřeruší, ale sbírají se v Errors.
+type SyncResult struct {
+	Created  int
+	Updated  int
+	Closed   int
+	Reopened int
+	// Skipped popisuje položky, které se záměrně nebo kvůli předchozí
+	// chybě nesynchronizovaly.
+	Skipped []string
+	Errors  []ItemError
+}
+
+// Failed říká, zda se některou položku nepodařilo synchronizovat.
+func (r *SyncResult) Failed() bool {
+	return len(r.Errors) > 0
+}
+
+func (r *SyncResult) String() string {
+	return i18n.T(i18n.MsgResultSummary,
+		r.Created, r.Updated, r.Closed, r.Reopened, len(r.Skipped), len(r.Errors))
+}
+
+// LogValue zapíše výsledek do strukturovaného logu jako skupinu počtů.
+func (r *SyncResult) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("created", r.Created),
+		slog.Int("updated", r.Updated),
+		slog.Int("closed", r.Closed),
+		slog.Int("reopened", r.Reopened),
+		slog.Int("skipped", len(r.Skipped)),
+		slog.Int("errors", len(r.Errors)),
+	)
+}
+
+// Merge přičte výsledek dalšího běhu, např. opačného směru.
+func (r *SyncResult) Merge(other *SyncResult) {
+	if other == nil {
+		return
+	}
+	r.Created += other.Created
+	r.Updated += other.Updated
+	r.Closed += other.Closed
+	r.Reopened += other.Reopened
+	r.Skipped = append(r.Skipped, other.Skipped...)
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
+func (r *SyncResult) record(result ActionResult) {
+	action := result.Action
+	ref := fmt.Sprintf("%s#%d", action.Repo, action.Issue)
+
+	switch {
+	case errors.Is(result.Err, ErrSkipped):
+		r.Skipped = append(r.Skipped, fmt.Sprintf("%s (%s): %v", ref, action.Type, result.Err))
+	case result.Err != nil:
+		r.Errors = append(r.Errors, ItemError{Item: ref, Action: action.Type, Err: result.Err})
+	default:
+		switch action.Type {
+		case ActionCreateTask:
+			r.Created++
+		case ActionUpdateTaskFields, ActionUpdateIssueTitle, ActionUpdateIssueLabel:
+			r.Updated++
+		case ActionCloseTask, ActionCloseIssue:
+			r.Closed++
+		case ActionReopenTask, ActionReopenIssue:
+			r.Reopened++
+		}
+	}
+}
+
+func (r *SyncResult) skip(item, reason string) {
+	r.Skipped = append(r.Skipped, item+": "+reason)
+}
+
+func (r *SyncResult) fail(item string, err error) {
+	r.Errors = append(r.Errors, ItemError{Item: item, Err: err})
+}
@@ -2,226 +2,955 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github-todoist-sync/internal/config"
 	"github-todoist-sync/internal/github"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+	"github-todoist-sync/internal/metrics"
+	"github-todoist-sync/internal/store"
 	"github-todoist-sync/internal/todoist"
 )
 
 type Service struct {
-	githubClient  *github.Client
-	todoistClient *todoist.Client
+	githubClient  IssueTracker
+	todoistClient TaskManager
 	config        *config.Config
-	project       *todoist.Project
+	store         *store.Store
+	repos         []*repoSync
+	tasks         *taskCache
+	executor      Executor
+	plan          []Action
+	progress      *progressTracker
+	// reported jsou konflikty (issue a pole) ohlášené v probíhající úplné
+	// synchronizaci. Konflikt, který nalezne směr GitHub → Todoist, najde
+	// znovu i opačný směr, ohlásí se ale jen jednou.
+	reported map[string]bool
 }
 
-func NewService(cfg *config.Config) (*Service, error) {
-	githubClient := github.NewClient(cfg.GitHub.Token, cfg.GitHub.Owner, cfg.GitHub.Repo)
-	todoistClient := todoist.NewClient(cfg.Todoist.Token)
+// repoSync je nastavená dvojice repozitář–projekt s vyřešenými Todoist ID.
+type repoSync struct {
+	pair      config.SyncPair
+	project   *todoist.Project
+	sectionID string
+}
+
+type fetchedRepo struct {
+	repo   *repoSync
+	issues []*github.Issue
+	// full značí, že issues obsahují celý repozitář, ne jen změny.
+	full    bool
+	cursor  store.Cursor
+	planned bool
+}
+
+func NewService(ctx context.Context, cfg *config.Config) (*Service, error) {
+	retry := todoist.RetryConfig(cfg.Todoist.Retry)
+	return NewServiceWithClients(ctx, cfg, github.NewClient(cfg.GitHub.Token), todoist.NewClient(cfg.Todoist.Token, retry))
+}
+
+// NewServiceWithClients vytvoří službu nad dodanými implementacemi klientů,
+// např. nad fake implementacemi v testech nebo nad jiným poskytovatelem.
+func NewServiceWithClients(ctx context.Context, cfg *config.Config, githubClient IssueTracker, todoistClient TaskManager) (*Service, error) {
+	linkStore, err := store.Open(cfg.App.StateFile)
+	if err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrStoreOpen, err)
+	}
 
 	service := &Service{
 		githubClient:  githubClient,
 		todoistClient: todoistClient,
 		config:        cfg,
+		store:         linkStore,
+		tasks:         newTaskCache(todoistClient),
+		progress:      &progressTracker{},
+	}
+	service.executor = &apiExecutor{
+		githubClient:  githubClient,
+		todoistClient: todoistClient,
+		store:         linkStore,
+		concurrency:   cfg.App.Concurrency,
+		progress:      service.progress,
+	}
+	if cfg.App.DryRun {
+		service.executor = &dryRunExecutor{
+			store:    linkStore,
+			record:   func(action Action) { service.plan = append(service.plan, action) },
+			progress: service.progress,
+		}
 	}
 
-	project, err := service.ensureProject()
-	if err != nil {
-		return nil, fmt.Errorf("chyba při nastavování projektu: %v", err)
+	if err := service.setupRepos(ctx); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrProjectSetup, err)
 	}
-	service.project = project
+	service.migrateLinks()
 
 	return service, nil
 }
 
-func (s *Service) ensureProject() (*todoist.Project, error) {
-	project, err := s.todoistClient.GetProjectByName(s.config.Todoist.ProjectName)
+func (s *Service) setupRepos(ctx context.Context) error {
+	projects := make(map[string]*todoist.Project)
+
+	for _, pair := range s.config.Pairs {
+		project, ok := projects[pair.Project]
+		if !ok {
+			var err error
+			if project, err = s.ensureProject(ctx, pair.Project); err != nil {
+				return err
+			}
+			projects[pair.Project] = project
+		}
+
+		repo := &repoSync{pair: pair, project: project}
+		if pair.Section != "" {
+			section, err := s.ensureSection(ctx, project, pair.Section)
+			if err != nil {
+				return err
+			}
+			repo.sectionID = section.ID
+		}
+
+		logging.Info(i18n.MsgPairProject, logging.Repo(pair.FullName()), "project", project.Name)
+		s.repos = append(s.repos, repo)
+	}
+
+	return nil
+}
+
+func (s *Service) ensureProject(ctx context.Context, name string) (*todoist.Project, error) {
+	project, err := s.todoistClient.GetProjectByName(ctx, name)
 	if err == nil {
-		log.Printf("Používám existující Todoist projekt: %s (ID: %s)", project.Name, project.ID)
+		logging.Info(i18n.MsgProjectExisting, "project", project.Name, "project_id", project.ID)
 		return project, nil
 	}
 
-	log.Printf("Vytvářím nový Todoist projekt: %s", s.config.Todoist.ProjectName)
-	project, err = s.todoistClient.CreateProject(s.config.Todoist.ProjectName)
+	logging.Info(i18n.MsgProjectCreating, "project", name)
+	project = &todoist.Project{Name: name}
+	err = s.setupAction(Action{Type: ActionCreateProject, Detail: name}, func() error {
+		project, err = s.todoistClient.CreateProject(ctx, name)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("nepodařilo se vytvořit projekt: %v", err)
+		return nil, i18n.Errorf(i18n.MsgErrProjectCreate, err)
 	}
 
-	log.Printf("Vytvořen nový Todoist projekt: %s (ID: %s)", project.Name, project.ID)
+	logging.Info(i18n.MsgProjectCreated, "project", project.Name, "project_id", project.ID)
 	return project, nil
 }
 
-func (s *Service) SyncFromGitHub(ctx context.Context) error {
-	log.Printf("Začínám synchronizaci GitHub → Todoist...")
+func (s *Service) ensureSection(ctx context.Context, project *todoist.Project, name string) (*todoist.Section, error) {
+	if project.ID != "" {
+		sections, err := s.todoistClient.GetSections(ctx, project.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, section := range sections {
+			if section.Name == name {
+				return section, nil
+			}
+		}
+	}
+
+	logging.Info(i18n.MsgSectionCreating, "section", name, "project", project.Name)
+	section := &todoist.Section{Name: name}
+	err := s.setupAction(Action{Type: ActionCreateSection, Detail: project.Name + " / " + name}, func() error {
+		var err error
+		section, err = s.todoistClient.CreateSection(ctx, project.ID, name)
+		return err
+	})
+	if err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrSectionCreate, err)
+	}
+
+	return section, nil
+}
+
+// migrateLinks přiřadí propojení uložená před podporou více repozitářů
+// prvnímu nastavenému repozitáři.
+func (s *Service) migrateLinks() {
+	legacy := s.repos[0]
+	for _, link := range s.store.Links() {
+		if link.Repo != "" {
+			continue
+		}
+		link.Repo = legacy.pair.FullName()
+		link.ProjectID = legacy.project.ID
+		s.store.Put(link)
+	}
+}
+
+func (s *Service) findRepo(fullName string) *repoSync {
+	for _, repo := range s.repos {
+		if strings.EqualFold(repo.pair.FullName(), fullName) {
+			return repo
+		}
+	}
+	return nil
+}
+
+func (s *Service) projects() []*todoist.Project {
+	var projects []*todoist.Project
+	seen := make(map[string]bool)
+	for _, repo := range s.repos {
+		if !seen[repo.project.ID] {
+			seen[repo.project.ID] = true
+			projects = append(projects, repo.project)
+		}
+	}
+	return projects
+}
+
+func (s *Service) SyncFromGitHub(ctx context.Context) (*SyncResult, error) {
+	result, _, err := s.syncFromGitHub(ctx, s.repos, false)
+	return result, err
+}
+
+// SyncRepo synchronizuje GitHub → Todoist jen jeden nastavený repozitář.
+// S rescan načte všechna issues místo změněných, např. po přejmenování
+// nebo smazání štítku, které čas změny issues neposune.
+func (s *Service) SyncRepo(ctx context.Context, owner, name string, rescan bool) (*SyncResult, error) {
+	repo := s.findRepo(owner + "/" + name)
+	if repo == nil {
+		return nil, i18n.Errorf(i18n.MsgErrRepoNotSynced, owner, name)
+	}
+
+	logging.Info(i18n.MsgRepoSync, logging.Repo(repo.pair.FullName()))
+	result, _, err := s.syncFromGitHub(ctx, []*repoSync{repo}, rescan)
+	return result, err
+}
+
+// SyncIssue promítne do Todoist aktuální stav jednoho issue.
+func (s *Service) SyncIssue(ctx context.Context, owner, name string, number int) (*SyncResult, error) {
+	repo := s.findRepo(owner + "/" + name)
+	if repo == nil {
+		return nil, i18n.Errorf(i18n.MsgErrRepoNotSynced, owner, name)
+	}
+
+	s.progress.start(PhaseToTodoist, 1)
+	issue, err := s.githubClient.GetIssue(ctx, repo.pair.Owner, repo.pair.Repo, number)
+	if err != nil {
+		return nil, err
+	}
+	s.progress.repoDone(1)
+
+	tasks, err := s.loadTasks(ctx, repo.project, func(task *todoist.Task) *github.Issue {
+		repoName, taskNumber := s.parseGitHubReference(repo.project, task.Description)
+		if issueKey(repoName, taskNumber) == issueKey(issue.FullName(), issue.Number) {
+			return issue
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.execute(ctx, s.planFromGitHub(repo, []*github.Issue{issue}, tasks))
+	return result, s.saveStore()
+}
+
+// SyncTask promítne do GitHubu změny jednoho úkolu, typicky z Todoist
+// webhooku. Smazaný úkol jen označí v propojení.
+func (s *Service) SyncTask(ctx context.Context, task *todoist.Task, deleted bool) (*SyncResult, error) {
+	var project *todoist.Project
+	for _, repo := range s.repos {
+		if repo.project.ID == task.ProjectID {
+			project = repo.project
+			break
+		}
+	}
+
+	s.progress.start(PhaseToGitHub, 1)
+	result := &SyncResult{}
+	link, linked := s.store.GetByTask(task.ID)
+	if !linked {
+		if project == nil {
+			return result, nil // Úkol nepatří do synchronizovaného projektu
+		}
+		repoName, number := s.parseGitHubReference(project, task.Description)
+		repo := s.findRepo(repoName)
+		if repo == nil {
+			return result, nil // Není to GitHub issue
+		}
 
-	issues, err := s.githubClient.GetIssues(ctx)
+		issue, err := s.githubClient.GetIssue(ctx, repo.pair.Owner, repo.pair.Repo, number)
+		if err != nil {
+			return nil, err
+		}
+		if link = s.rebuildLink(task, issue); link == nil {
+			return result, nil
+		}
+	}
+
+	if deleted {
+		logging.Info(i18n.MsgTaskDeleted, logging.Issue(link.Ref()), logging.TaskID(link.TaskID))
+		link.TaskDeleted = true
+		s.store.Put(link)
+		result.skip(link.Ref(), i18n.T(i18n.MsgSkipTaskDeleted))
+		return result, s.saveStore()
+	}
+	if link.IssueDeleted {
+		return result, nil // Issue bylo smazáno nebo přesunuto
+	}
+
+	repo := s.findRepo(link.Repo)
+	if repo == nil || !pendingToGitHub(repo.pair, link, task) {
+		return result, nil
+	}
+
+	issue, err := s.githubClient.GetIssue(ctx, repo.pair.Owner, repo.pair.Repo, link.IssueNumber)
 	if err != nil {
-		return fmt.Errorf("chyba při získávání GitHub issues: %v", err)
+		return nil, err
+	}
+
+	plan := &Plan{}
+	plan.add(planToGitHub(repo.pair, link, task, issue))
+	result = s.execute(ctx, plan)
+	return result, s.saveStore()
+}
+
+// syncFromGitHub vrací vedle výsledku i úspěšně načtené repozitáře, aby je
+// FullSync mohl předat synchronizaci opačným směrem. Repozitář, který se
+// nepodaří načíst, se zapíše mezi chyby výsledku a ostatní pokračují.
+func (s *Service) syncFromGitHub(ctx context.Context, repos []*repoSync, rescan bool) (*SyncResult, []*fetchedRepo, error) {
+	logging.Info(i18n.MsgToTodoistStarted)
+	started := time.Now()
+	s.progress.start(PhaseToTodoist, len(repos))
+
+	var errs []error
+	var fetched []*fetchedRepo
+	var fetchErrs []ItemError
+
+	for _, repo := range repos {
+		f, err := s.fetchIssues(ctx, repo, rescan)
+		s.progress.repoDone(1)
+		if err != nil {
+			fetchErrs = append(fetchErrs, ItemError{
+				Item: repo.pair.FullName(),
+				Err:  i18n.Errorf(i18n.MsgErrIssuesFetch, err),
+			})
+			continue
+		}
+		fetched = append(fetched, f)
+	}
+	s.dropMissingIssues(fetched)
+	issuesByRef := fetchedIssues(fetched)
+
+	projectTasks := make(map[string]map[string]*todoist.Task)
+	plan := &Plan{}
+	for _, f := range fetched {
+		project := f.repo.project
+		tasks, ok := projectTasks[project.ID]
+		if !ok {
+			var err error
+			tasks, err = s.loadTasks(ctx, project, func(task *todoist.Task) *github.Issue {
+				repoName, number := s.parseGitHubReference(project, task.Description)
+				return issuesByRef[issueKey(repoName, number)]
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			projectTasks[project.ID] = tasks
+		}
+
+		plan.merge(s.planFromGitHub(f.repo, f.issues, tasks))
+		f.planned = true
+	}
+
+	result := s.execute(ctx, plan)
+	if !result.Failed() {
+		// Kurzor posuneme, jen když se všechny změny propsaly, jinak by
+		// se neúspěšná issues v příštím běhu už nenačetla.
+		for _, f := range fetched {
+			if f.planned {
+				s.store.SetCursor(cursorKey(f.repo), f.cursor)
+			}
+		}
+	}
+	result.Errors = append(result.Errors, fetchErrs...)
+	if err := s.saveStore(); err != nil {
+		errs = append(errs, err)
 	}
 
-	log.Printf("Nalezeno %d GitHub issues", len(issues))
+	logging.Info(i18n.MsgToTodoistDone, "result", result, logging.Duration(time.Since(started)), "github_rate_limit", s.githubClient.RateLimit())
+	return result, fetched, errors.Join(errs...)
+}
+
+// fetchedIssues vrací issues načtených repozitářů podle issueKey.
+func fetchedIssues(fetched []*fetchedRepo) map[string]*github.Issue {
+	issues := make(map[string]*github.Issue)
+	for _, f := range fetched {
+		for _, issue := range f.issues {
+			issues[issueKey(issue.FullName(), issue.Number)] = issue
+		}
+	}
+	return issues
+}
 
-	existingTasks, err := s.todoistClient.GetTasks(s.project.ID)
+// fetchIssues načte issues repozitáře. Běžně jen ta změněná od minulé
+// synchronizace, jednou za FullRescanInterval všechna, aby se odhalila
+// smazaná a přesunutá issues. S rescan načte všechna hned. Nový kurzor
+// vrací ve fetchedRepo, uloží se až po úspěšném provedení plánu.
+func (s *Service) fetchIssues(ctx context.Context, repo *repoSync, rescan bool) (*fetchedRepo, error) {
+	owner, name := repo.pair.Owner, repo.pair.Repo
+	cursor, ok := s.store.Cursor(cursorKey(repo))
+	now := time.Now()
+
+	if rescan || !ok || now.Sub(cursor.FullScanAt) >= s.config.App.FullRescanInterval {
+		issues, err := s.githubClient.GetIssues(ctx, owner, name)
+		if err != nil {
+			return nil, err
+		}
+		logging.Info(i18n.MsgIssuesLoaded, logging.Repo(repo.pair.FullName()), "count", len(issues), "incremental", false)
+		return &fetchedRepo{
+			repo:   repo,
+			issues: issues,
+			full:   true,
+			cursor: store.Cursor{Since: latestUpdate(issues, time.Time{}), FullScanAt: now},
+		}, nil
+	}
+
+	list, err := s.githubClient.GetIssuesSince(ctx, owner, name, cursor.Since, cursor.ETag)
 	if err != nil {
-		return fmt.Errorf("chyba při získávání Todoist úkolů: %v", err)
+		return nil, err
+	}
+	if list.NotModified {
+		logging.Info(i18n.MsgIssuesNotModified, logging.Repo(repo.pair.FullName()))
+	} else {
+		logging.Info(i18n.MsgIssuesLoaded, logging.Repo(repo.pair.FullName()), "count", len(list.Issues), "incremental", true)
 	}
 
-	taskMap := make(map[int]*todoist.Task)
-	for _, task := range existingTasks {
-		if issueNum := s.extractGitHubIssueNumber(task.Description); issueNum != 0 {
-			taskMap[issueNum] = task
+	cursor.Since = latestUpdate(list.Issues, cursor.Since)
+	cursor.ETag = list.ETag
+	return &fetchedRepo{repo: repo, issues: list.Issues, cursor: cursor}, nil
+}
+
+// dropMissingIssues označí propojení issues, která při úplném načtení
+// v repozitáři chyběla, protože byla smazána nebo přesunuta jinam. Propojení
+// zůstává, aby se úkol s odkazem na issue v popisu nepovažoval za
+// nepropojený a nezkoušelo se při každém běhu načíst neexistující issue.
+// Issue přesunuté do jiného synchronizovaného repozitáře si propojení
+// ponechá.
+func (s *Service) dropMissingIssues(fetched []*fetchedRepo) {
+	seen := make(map[int64]bool)
+	for _, f := range fetched {
+		for _, issue := range f.issues {
+			seen[issue.ID] = true
 		}
 	}
 
-	var syncedCount int
+	for _, f := range fetched {
+		if !f.full {
+			continue
+		}
+		for _, link := range s.store.Links() {
+			if seen[link.IssueID] || link.IssueDeleted || !strings.EqualFold(link.Repo, f.repo.pair.FullName()) {
+				continue
+			}
+			logging.Info(i18n.MsgIssueMissing, logging.Issue(link.Ref()), logging.TaskID(link.TaskID))
+			link.IssueDeleted = true
+			s.store.Put(link)
+		}
+	}
+}
+
+func cursorKey(repo *repoSync) string {
+	return strings.ToLower(repo.pair.FullName())
+}
+
+// latestUpdate vrací nejpozdější čas změny mezi issues, nejméně since.
+func latestUpdate(issues []*github.Issue, since time.Time) time.Time {
+	for _, issue := range issues {
+		if issue.UpdatedAt.After(since) {
+			since = issue.UpdatedAt
+		}
+	}
+	return since
+}
+
+// planFromGitHub naplánuje pro issues jednoho repozitáře založení nových
+// úkolů a aktualizaci propojených.
+func (s *Service) planFromGitHub(repo *repoSync, issues []*github.Issue, taskMap map[string]*todoist.Task) *Plan {
+	plan := &Plan{}
 	for _, issue := range issues {
 		if issue.IsPullReq {
 			continue
 		}
 
-		existingTask, exists := taskMap[issue.Number]
+		link, linked := s.store.Get(issue.ID)
+		if !linked {
+			if repo.pair.Filters.Match(issue.State, issue.Labels) {
+				plan.add(planCreateTask(repo, issue))
+			}
+			continue
+		}
 
+		existingTask, exists := taskMap[link.TaskID]
 		if !exists {
-			if err := s.createTodoistTask(issue); err != nil {
-				log.Printf("Chyba při vytváření úkolu pro issue #%d: %v", issue.Number, err)
-				continue
+			plan.skip(issue.Ref(), i18n.T(i18n.MsgSkipTaskDeleted))
+			continue
+		}
+
+		plan.add(planToTodoist(repo.pair, link, existingTask, issue))
+	}
+	return plan
+}
+
+func (s *Service) SyncToGitHub(ctx context.Context) (*SyncResult, error) {
+	return s.syncToGitHub(ctx, s.repos, nil)
+}
+
+// pendingTask je úkol se změnou, kterou je třeba promítnout do GitHubu.
+type pendingTask struct {
+	task   *todoist.Task
+	repo   *repoSync
+	link   *store.Link
+	number int
+}
+
+// syncToGitHub promítne do GitHubu změny úkolů z repozitářů repos. Issues
+// hledá nejprve v issues načtených toutéž synchronizací, chybějící načte
+// hromadně.
+func (s *Service) syncToGitHub(ctx context.Context, repos []*repoSync, issues map[string]*github.Issue) (*SyncResult, error) {
+	logging.Info(i18n.MsgToGitHubStarted)
+	started := time.Now()
+	s.progress.start(PhaseToGitHub, len(repos))
+
+	var errs []error
+	var pending []pendingTask
+	for _, project := range s.projects() {
+		tasks, err := s.loadTasks(ctx, project, nil)
+		for _, repo := range repos {
+			if repo.project.ID == project.ID {
+				s.progress.repoDone(1)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, task := range tasks {
+			repoName, issueNumber := s.parseGitHubReference(project, task.Description)
+			link, linked := s.store.GetByTask(task.ID)
+			if linked {
+				repoName, issueNumber = link.Repo, link.IssueNumber
+			} else if issueNumber == 0 {
+				continue // Není to GitHub issue
 			}
-			syncedCount++
-			log.Printf("Vytvořen úkol pro issue #%d: %s", issue.Number, issue.Title)
-		} else {
-			if err := s.updateTodoistTask(existingTask, issue); err != nil {
-				log.Printf("Chyba při aktualizaci úkolu pro issue #%d: %v", issue.Number, err)
+
+			repo := s.findRepo(repoName)
+			if repo == nil {
+				continue // Repozitář není nastaven pro synchronizaci
+			}
+			if !slices.Contains(repos, repo) {
+				continue // Repozitář se v tomto běhu nepodařilo načíst
+			}
+			if linked && link.IssueDeleted {
+				continue // Issue bylo smazáno nebo přesunuto
+			}
+			if linked && !pendingToGitHub(repo.pair, link, task) {
+				continue // Úkol se od poslední synchronizace nezměnil
+			}
+
+			pending = append(pending, pendingTask{task: task, repo: repo, link: link, number: issueNumber})
+		}
+	}
+
+	issues = s.fetchMissingIssues(ctx, pending, issues)
+
+	plan := &Plan{}
+	var unavailable []string
+	for _, p := range pending {
+		issue := issues[issueKey(p.repo.pair.FullName(), p.number)]
+		if issue == nil {
+			unavailable = append(unavailable, fmt.Sprintf("%s#%d", p.repo.pair.FullName(), p.number))
+			continue
+		}
+
+		link := p.link
+		if link == nil {
+			if link = s.rebuildLink(p.task, issue); link == nil {
 				continue
 			}
-			log.Printf("Aktualizován úkol pro issue #%d", issue.Number)
 		}
+
+		plan.add(planToGitHub(p.repo.pair, link, p.task, issue))
 	}
 
-	log.Printf("GitHub → Todoist synchronizace dokončena. Zpracováno: %d úkolů", syncedCount)
-	return nil
+	result := s.execute(ctx, plan)
+	for _, ref := range unavailable {
+		result.fail(ref, i18n.Errorf(i18n.MsgErrIssueUnavailable))
+	}
+	if err := s.saveStore(); err != nil {
+		errs = append(errs, err)
+	}
+
+	logging.Info(i18n.MsgToGitHubDone, "result", result, logging.Duration(time.Since(started)), "github_rate_limit", s.githubClient.RateLimit())
+	return result, errors.Join(errs...)
 }
 
-func (s *Service) SyncToGitHub(ctx context.Context) error {
-	log.Printf("Začínám synchronizaci Todoist → GitHub...")
+// fetchMissingIssues doplní issues, která úkoly potřebují a v known
+// chybí. Každé issue stojí jeden požadavek, výpis celého repozitáře jeden
+// požadavek na stránku. Výpis se použije, jen když má méně stránek, než
+// kolik issues chybí.
+func (s *Service) fetchMissingIssues(ctx context.Context, pending []pendingTask, known map[string]*github.Issue) map[string]*github.Issue {
+	issues := make(map[string]*github.Issue, len(known))
+	for key, issue := range known {
+		issues[key] = issue
+	}
 
-	tasks, err := s.todoistClient.GetTasks(s.project.ID)
-	if err != nil {
-		return fmt.Errorf("chyba při získávání Todoist úkolů: %v", err)
+	missing := make(map[*repoSync][]int)
+	for _, p := range pending {
+		key := issueKey(p.repo.pair.FullName(), p.number)
+		if _, ok := issues[key]; !ok {
+			missing[p.repo] = append(missing[p.repo], p.number)
+			issues[key] = nil
+		}
 	}
 
-	var syncedCount int
-	for _, task := range tasks {
-		issueNumber := s.extractGitHubIssueNumber(task.Description)
-		if issueNumber == 0 {
-			continue // Není to GitHub issue
+	for repo, numbers := range missing {
+		owner, name := repo.pair.Owner, repo.pair.Repo
+		if listingPages(repo, numbers, s.store.Links()) >= len(numbers) {
+			for _, number := range numbers {
+				issue, err := s.githubClient.GetIssue(ctx, owner, name, number)
+				if err != nil {
+					logging.Error(i18n.MsgIssueFetchFailed, logging.Issue(issueKey(repo.pair.FullName(), number)), logging.Err(err))
+					continue
+				}
+				issues[issueKey(issue.FullName(), issue.Number)] = issue
+			}
+			continue
 		}
 
-		issue, err := s.githubClient.GetIssue(ctx, issueNumber)
+		all, err := s.githubClient.GetIssues(ctx, owner, name)
 		if err != nil {
-			log.Printf("Chyba při získávání issue #%d: %v", issueNumber, err)
+			logging.Error(i18n.MsgIssuesFetchFailed, logging.Repo(repo.pair.FullName()), logging.Err(err))
 			continue
 		}
+		for _, issue := range all {
+			issues[issueKey(issue.FullName(), issue.Number)] = issue
+		}
+	}
 
-		if err := s.syncTaskStateToGitHub(ctx, task, issue); err != nil {
-			log.Printf("Chyba při synchronizaci stavu issue #%d: %v", issueNumber, err)
-			continue
+	return issues
+}
+
+// listingPages odhadne počet stránek výpisu všech issues repozitáře.
+// Issues a pull requesty sdílejí číslování a výpis obsahuje obojí, takže
+// nejvyšší známé číslo je horní mez počtu položek.
+func listingPages(repo *repoSync, numbers []int, links []*store.Link) int {
+	highest := 0
+	for _, number := range numbers {
+		highest = max(highest, number)
+	}
+	for _, link := range links {
+		if strings.EqualFold(link.Repo, repo.pair.FullName()) {
+			highest = max(highest, link.IssueNumber)
 		}
+	}
+	return (highest + github.PageSize - 1) / github.PageSize
+}
+
+// FullSync synchronizuje oba směry. Issues načtená pro směr GitHub → Todoist
+// se použijí i pro opačný směr.
+func (s *Service) FullSync(ctx context.Context) (*SyncResult, error) {
+	logging.Info(i18n.MsgFullSyncStarted)
+	started := time.Now()
 
-		syncedCount++
+	// Chyba jednoho směru nebo repozitáře nesmí zastavit zbytek
+	// synchronizace, Todoist → GitHub proto běží pro všechny repozitáře,
+	// které se podařilo načíst.
+	s.reported = make(map[string]bool)
+	defer func() { s.reported = nil }()
+
+	var errs []error
+	result, fetched, err := s.syncFromGitHub(ctx, s.repos, false)
+	if err != nil {
+		errs = append(errs, i18n.Errorf(i18n.MsgErrToTodoist, err))
 	}
 
-	log.Printf("Todoist → GitHub synchronizace dokončena. Zpracováno: %d úkolů", syncedCount)
-	return nil
+	repos := make([]*repoSync, 0, len(fetched))
+	for _, f := range fetched {
+		repos = append(repos, f.repo)
+	}
+	toGitHub, err := s.syncToGitHub(ctx, repos, fetchedIssues(fetched))
+	result.Merge(toGitHub)
+	if err != nil {
+		errs = append(errs, i18n.Errorf(i18n.MsgErrToGitHub, err))
+	}
+
+	logging.Info(i18n.MsgFullSyncDone, "result", result, logging.Duration(time.Since(started)))
+	return result, errors.Join(errs...)
 }
 
-func (s *Service) FullSync(ctx context.Context) error {
-	log.Printf("Spouštím úplnou synchronizaci...")
+// Progress vrací průběh právě probíhající synchronizace. Po jejím
+// skončení zůstávají počty posledního směru.
+func (s *Service) Progress() Progress {
+	return s.progress.snapshot()
+}
 
-	if err := s.SyncFromGitHub(ctx); err != nil {
-		return fmt.Errorf("chyba při synchronizaci GitHub → Todoist: %v", err)
+func (s *Service) execute(ctx context.Context, plan *Plan) *SyncResult {
+	summary := &SyncResult{Skipped: plan.skipped}
+	for _, ip := range plan.issues {
+		for _, c := range ip.conflicts {
+			if key := ip.link.Ref() + "/" + c.field; s.reported != nil {
+				if s.reported[key] {
+					continue
+				}
+				s.reported[key] = true
+			}
+			logging.Warn(i18n.MsgConflict, logging.Issue(ip.link.Ref()), "field", c.field, "detail", c.String())
+			if c.unresolved {
+				summary.skip(ip.link.Ref(), c.String())
+			}
+		}
 	}
 
-	if err := s.SyncToGitHub(ctx); err != nil {
-		return fmt.Errorf("chyba při synchronizaci Todoist → GitHub: %v", err)
+	for _, ip := range plan.issues {
+		s.progress.planned(len(ip.actions))
 	}
+	for _, result := range s.executor.Execute(ctx, plan) {
+		summary.record(result)
+		action := result.Action
+		ref := fmt.Sprintf("%s#%d", action.Repo, action.Issue)
+		if !s.config.App.DryRun {
+			metrics.ObserveAction(action.Repo, string(action.Type), actionOutcome(result.Err))
+		}
+		if result.Err != nil {
+			logging.Error(i18n.MsgActionFailed, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)), logging.Err(result.Err))
+			continue
+		}
 
-	log.Printf("Úplná synchronizace dokončena")
-	return nil
+		if s.config.App.DryRun {
+			continue
+		}
+		switch action.Type {
+		case ActionCreateTask:
+			logging.Info(i18n.MsgTaskCreated, logging.Issue(ref), logging.Action(string(action.Type)), "detail", action.Detail)
+		case ActionUpdateTaskFields:
+			logging.Info(i18n.MsgTaskUpdated, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)), "detail", action.Detail)
+		case ActionCloseTask:
+			logging.Info(i18n.MsgTaskClosed, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)))
+		case ActionReopenTask:
+			logging.Info(i18n.MsgTaskReopened, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)))
+		case ActionCloseIssue:
+			logging.Info(i18n.MsgIssueClosed, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)))
+		case ActionReopenIssue:
+			logging.Info(i18n.MsgIssueReopened, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)))
+		case ActionUpdateIssueLabel:
+			logging.Info(i18n.MsgIssueLabelsUpdated, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)), "detail", action.Detail)
+		case ActionUpdateIssueTitle:
+			logging.Info(i18n.MsgIssueTitleUpdated, logging.Issue(ref), logging.TaskID(action.TaskID), logging.Action(string(action.Type)), "detail", action.Detail)
+		}
+	}
+	return summary
 }
 
-func (s *Service) createTodoistTask(issue *github.Issue) error {
-	task := &todoist.CreateTaskRequest{
-		Content:     issue.Title,
-		Description: todoist.FormatGitHubReference(issue.Number, issue.HTMLURL),
-		ProjectID:   s.project.ID,
-		Priority:    todoist.GetLabelPriority(issue.Labels),
-		Labels:      s.convertLabels(issue.Labels),
+func actionOutcome(err error) string {
+	switch {
+	case errors.Is(err, ErrSkipped):
+		return "skipped"
+	case err != nil:
+		return "error"
 	}
+	return "ok"
+}
 
-	_, err := s.todoistClient.CreateTask(task)
-	return err
+// setupAction provede přípravnou změnu (projekt, sekce), v režimu dry-run
+// ji jen zaznamená do plánu.
+func (s *Service) setupAction(action Action, fn func() error) error {
+	if s.config.App.DryRun {
+		s.plan = append(s.plan, action)
+		return nil
+	}
+	return fn()
 }
 
-func (s *Service) updateTodoistTask(task *todoist.Task, issue *github.Issue) error {
-	updates := make(map[string]interface{})
+// Plan vrací akce zaznamenané v režimu dry-run.
+func (s *Service) Plan() []Action {
+	return s.plan
+}
+
+// RateLimit vrací poslední známý stav limitu GitHub API.
+func (s *Service) RateLimit() github.RateLimit {
+	return s.githubClient.RateLimit()
+}
+
+func (s *Service) saveStore() error {
+	if s.config.App.DryRun {
+		return nil
+	}
+	return s.store.Save()
+}
 
-	if task.Content != issue.Title {
-		updates["content"] = issue.Title
+// loadTasks vrací úkoly projektu podle ID včetně propojených úkolů, které
+// mezi aktivními chybí, protože byly dokončeny. Propojený úkol, který není
+// ani mezi dokončenými, je označen jako smazaný. Funkce resolve umožňuje
+// obnovit propojení z popisu úkolu ještě před touto kontrolou.
+func (s *Service) loadTasks(ctx context.Context, project *todoist.Project, resolve func(*todoist.Task) *github.Issue) (map[string]*todoist.Task, error) {
+	if project.ID == "" {
+		return map[string]*todoist.Task{}, nil // Projekt teprve vznikne (dry-run)
 	}
 
-	newPriority := todoist.GetLabelPriority(issue.Labels)
-	if task.Priority != newPriority {
-		updates["priority"] = newPriority
+	if err := s.tasks.refresh(ctx); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrTodoistTasks, err)
 	}
 
-	shouldBeClosed := issue.State == "closed"
-	if task.IsCompleted != shouldBeClosed {
-		if shouldBeClosed {
-			return s.todoistClient.CloseTask(task.ID)
-		} else {
-			return s.todoistClient.ReopenTask(task.ID)
+	tasks := s.tasks.project(project.ID)
+	for _, task := range tasks {
+		if resolve != nil && !task.IsCompleted {
+			s.rebuildLink(task, resolve(task))
 		}
 	}
 
-	if len(updates) > 0 {
-		return s.todoistClient.UpdateTask(task.ID, updates)
+	var missing []*store.Link
+	var since time.Time
+	for _, link := range s.store.Links() {
+		if link.ProjectID != project.ID || link.TaskDeleted || tasks[link.TaskID] != nil {
+			continue
+		}
+		missing = append(missing, link)
+		if !link.Task.IsCompleted && !s.tasks.deleted[link.TaskID] && (since.IsZero() || link.SyncedAt.Before(since)) {
+			since = link.SyncedAt
+		}
 	}
 
-	return nil
+	completed := make(map[string]*todoist.CompletedTask)
+	if !since.IsZero() {
+		completedTasks, err := s.todoistClient.GetCompletedTasks(ctx, project.ID, since)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range completedTasks {
+			completed[task.TaskID] = task
+		}
+	}
+
+	for _, link := range missing {
+		item, isCompleted := completed[link.TaskID]
+		if s.tasks.deleted[link.TaskID] || (!isCompleted && !link.Task.IsCompleted) {
+			logging.Info(i18n.MsgTaskDeleted, logging.Issue(link.Ref()), logging.TaskID(link.TaskID))
+			link.TaskDeleted = true
+			s.store.Put(link)
+			continue
+		}
+
+		task := &todoist.Task{
+			ID:          link.TaskID,
+			ProjectID:   project.ID,
+			Content:     link.Task.Content,
+			Priority:    link.Task.Priority,
+			IsCompleted: true,
+		}
+		if isCompleted {
+			task.Content = item.Content
+			task.UpdatedAt = item.CompletedAt
+		}
+		tasks[task.ID] = task
+		s.tasks.put(task)
+	}
+
+	return tasks, nil
 }
 
-func (s *Service) syncTaskStateToGitHub(ctx context.Context, task *todoist.Task, issue *github.Issue) error {
-	if task.IsCompleted && issue.State == "open" {
-		log.Printf("Uzavírám GitHub issue #%d (dokončeno v Todoist)", issue.Number)
-		return s.githubClient.UpdateIssueState(ctx, issue.Number, "closed")
+// rebuildLink obnoví propojení z popisu úkolu, pokud ho úložiště ještě
+// nezná. Značka v popisu slouží jen jako záloha pro sestavení úložiště.
+func (s *Service) rebuildLink(task *todoist.Task, issue *github.Issue) *store.Link {
+	if issue == nil || issue.IsPullReq {
+		return nil
+	}
+	if link, ok := s.store.GetByTask(task.ID); ok {
+		return link
+	}
+	if _, ok := s.store.Get(issue.ID); ok {
+		return nil
 	}
 
-	if !task.IsCompleted && issue.State == "closed" {
-		log.Printf("Otevírám GitHub issue #%d (znovu otevřeno v Todoist)", issue.Number)
-		return s.githubClient.UpdateIssueState(ctx, issue.Number, "open")
+	var projectID string
+	if repo := s.findRepo(issue.FullName()); repo != nil {
+		projectID = repo.project.ID
 	}
 
-	return nil
+	logging.Info(i18n.MsgLinkRestored, logging.Issue(issue.Ref()), logging.TaskID(task.ID))
+	link := newLink(issue, projectID, task.ID, taskSnapshot(task))
+	s.store.Put(&link)
+	return &link
+}
+
+func newLink(issue *github.Issue, projectID, taskID string, task store.TaskSnapshot) store.Link {
+	return store.Link{
+		IssueID:     issue.ID,
+		Repo:        issue.FullName(),
+		IssueNumber: issue.Number,
+		TaskID:      taskID,
+		ProjectID:   projectID,
+		Issue:       issueSnapshot(issue),
+		Task:        task,
+		SyncedAt:    time.Now(),
+	}
+}
+
+func issueSnapshot(issue *github.Issue) store.IssueSnapshot {
+	return store.IssueSnapshot{
+		Title:     issue.Title,
+		State:     issue.State,
+		Labels:    issue.Labels,
+		UpdatedAt: issue.UpdatedAt,
+	}
+}
+
+func taskSnapshot(task *todoist.Task) store.TaskSnapshot {
+	return store.TaskSnapshot{
+		Content:     task.Content,
+		Priority:    task.Priority,
+		IsCompleted: task.IsCompleted,
+	}
 }
 
-func (s *Service) extractGitHubIssueNumber(description string) int {
-	if strings.Contains(description, "GitHub Issue #") {
-		parts := strings.Split(description, "GitHub Issue #")
-		if len(parts) > 1 {
-			numberPart := strings.Split(parts[1], ":")[0]
-			if number, err := strconv.Atoi(numberPart); err == nil {
-				return number
+// parseGitHubReference čte odkaz na issue z popisu úkolu. Starší úkoly
+// nesou jen "GitHub Issue #číslo:", ty patří prvnímu repozitáři projektu.
+func (s *Service) parseGitHubReference(project *todoist.Project, description string) (string, int) {
+	_, reference, found := strings.Cut(description, "GitHub Issue ")
+	if !found {
+		return "", 0
+	}
+
+	reference, _, _ = strings.Cut(reference, ":")
+	repoName, numberPart, found := strings.Cut(reference, "#")
+	if !found {
+		return "", 0
+	}
+
+	number, err := strconv.Atoi(numberPart)
+	if err != nil {
+		return "", 0
+	}
+
+	if repoName == "" {
+		for _, repo := range s.repos {
+			if repo.project.ID == project.ID {
+				return repo.pair.FullName(), number
 			}
 		}
+		return "", 0
 	}
-	return 0
+
+	return repoName, number
+}
+
+func issueKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(repo), number)
 }
 
-func (s *Service) convertLabels(githubLabels []string) []string {
+func convertLabels(githubLabels []string) []string {
 	var todoistLabels []string
 	for _, label := range githubLabels {
 		cleanLabel := strings.ReplaceAll(strings.ToLower(label), " ", "_")
@@ -0,0 +1,73 @@
+package sync
+
+import (
+	"context"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+	"github-todoist-sync/internal/todoist"
+)
+
+// taskCache drží úkoly všech projektů načtené přes Sync API. První
+// obnovení stáhne úplný stav, další už jen změny od posledního sync_token,
+// takže úplná synchronizace nestahuje úkoly dvakrát.
+type taskCache struct {
+	manager TaskManager
+	token   string
+	tasks   map[string]*todoist.Task
+	deleted map[string]bool
+}
+
+func newTaskCache(manager TaskManager) *taskCache {
+	return &taskCache{
+		manager: manager,
+		token:   todoist.FullSyncToken,
+		tasks:   make(map[string]*todoist.Task),
+		deleted: make(map[string]bool),
+	}
+}
+
+// refresh promítne do cache změny od posledního volání.
+func (c *taskCache) refresh(ctx context.Context) error {
+	resp, err := c.manager.SyncItems(ctx, c.token)
+	if err != nil {
+		return err
+	}
+
+	if resp.FullSync {
+		c.tasks = make(map[string]*todoist.Task)
+		c.deleted = make(map[string]bool)
+	}
+	for _, item := range resp.Items {
+		if item.IsDeleted {
+			delete(c.tasks, item.ID)
+			c.deleted[item.ID] = true
+			continue
+		}
+		c.tasks[item.ID] = item.Task()
+		delete(c.deleted, item.ID)
+	}
+
+	if resp.FullSync || len(resp.Items) > 0 {
+		logging.Info(i18n.MsgTasksLoaded, "count", len(resp.Items), "incremental", !resp.FullSync)
+	}
+	c.token = resp.SyncToken
+	return nil
+}
+
+// project vrací úkoly jednoho projektu.
+func (c *taskCache) project(projectID string) map[string]*todoist.Task {
+	tasks := make(map[string]*todoist.Task)
+	for id, task := range c.tasks {
+		if task.ProjectID == projectID {
+			tasks[id] = task
+		}
+	}
+	return tasks
+}
+
+// put uloží úkol, o kterém se služba dozvěděla jinak než přes Sync API,
+// např. dohledaný dokončený úkol.
+func (c *taskCache) put(task *todoist.Task) {
+	c.tasks[task.ID] = task
+}
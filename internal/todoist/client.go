@@ -2,21 +2,36 @@ package todoist
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/metrics"
+	"github-todoist-sync/internal/redact"
 )
 
 const (
-	baseURL = "https://api.todoist.com/rest/v2"
+	baseURL     = "https://api.todoist.com/rest/v2"
+	syncBaseURL = "https://api.todoist.com/sync/v9"
+
+	completedPageSize = 200
+
+	// maxErrorBody omezuje, kolik z těla chybové odpovědi se dostane do
+	// chyby.
+	maxErrorBody = 1024
 )
 
 type Client struct {
 	token      string
 	httpClient *http.Client
+	retry      RetryConfig
 }
 
 type Project struct {
@@ -34,6 +49,13 @@ type Project struct {
 	ParentID     string `json:"parent_id,omitempty"`
 }
 
+type Section struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Order     int    `json:"order"`
+	Name      string `json:"name"`
+}
+
 type Task struct {
 	ID           string    `json:"id"`
 	ProjectID    string    `json:"project_id"`
@@ -49,11 +71,63 @@ type Task struct {
 	URL          string    `json:"url"`
 	CommentCount int       `json:"comment_count"`
 	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 	CreatorID    string    `json:"creator_id"`
 	AssigneeID   string    `json:"assignee_id,omitempty"`
 	AssignerID   string    `json:"assigner_id,omitempty"`
 }
 
+type CompletedTask struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	ProjectID   string    `json:"project_id"`
+	SectionID   string    `json:"section_id,omitempty"`
+	Content     string    `json:"content"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+type completedTasksResponse struct {
+	Items []*CompletedTask `json:"items"`
+}
+
+// Item je úkol ve tvaru Sync API, ve kterém ho posílají i webhooky.
+type Item struct {
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	SectionID   string    `json:"section_id,omitempty"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	Priority    int       `json:"priority"`
+	Labels      []string  `json:"labels"`
+	Checked     bool      `json:"checked"`
+	IsDeleted   bool      `json:"is_deleted"`
+	AddedAt     time.Time `json:"added_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+func (i *Item) Task() *Task {
+	updatedAt := i.UpdatedAt
+	if updatedAt.IsZero() && i.Checked {
+		updatedAt = i.CompletedAt
+	}
+
+	return &Task{
+		ID:          i.ID,
+		ProjectID:   i.ProjectID,
+		SectionID:   i.SectionID,
+		ParentID:    i.ParentID,
+		Content:     i.Content,
+		Description: i.Description,
+		Priority:    i.Priority,
+		Labels:      i.Labels,
+		IsCompleted: i.Checked,
+		CreatedAt:   i.AddedAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
 type Due struct {
 	String      string `json:"string"`
 	Date        string `json:"date"`
@@ -78,29 +152,36 @@ type CreateTaskRequest struct {
 	AssigneeID  string   `json:"assignee_id,omitempty"`
 }
 
-func NewClient(token string) *Client {
+// NewClient vytvoří klienta a token zaregistruje ke skrývání, aby se
+// nedostal do logu ani do chyb, např. když ho API vrátí v těle odpovědi.
+func NewClient(token string, retry RetryConfig) *Client {
+	redact.Add(token)
 	return &Client{
-		token:      token,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		token: token,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: metrics.Transport("todoist", http.DefaultTransport),
+		},
+		retry: retry,
 	}
 }
 
-func (c *Client) GetProjects() ([]*Project, error) {
-	req, err := c.createRequest("GET", "/projects", nil)
+func (c *Client) GetProjects(ctx context.Context) ([]*Project, error) {
+	req, err := c.createRequest(ctx, "GET", "/projects", nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var projects []*Project
 	if err := c.doRequest(req, &projects); err != nil {
-		return nil, fmt.Errorf("chyba při získávání projektů: %v", err)
+		return nil, i18n.Errorf(i18n.MsgErrProjectsGet, err)
 	}
 
 	return projects, nil
 }
 
-func (c *Client) GetProjectByName(name string) (*Project, error) {
-	projects, err := c.GetProjects()
+func (c *Client) GetProjectByName(ctx context.Context, name string) (*Project, error) {
+	projects, err := c.GetProjects(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -111,99 +192,165 @@ func (c *Client) GetProjectByName(name string) (*Project, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("projekt '%s' nebyl nalezen", name)
+	return nil, i18n.Errorf(i18n.MsgErrProjectNotFound, name)
 }
 
-func (c *Client) CreateProject(name string) (*Project, error) {
+func (c *Client) CreateProject(ctx context.Context, name string) (*Project, error) {
 	payload := map[string]string{"name": name}
 
-	req, err := c.createRequest("POST", "/projects", payload)
+	req, err := c.createRequest(ctx, "POST", "/projects", payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var project Project
 	if err := c.doRequest(req, &project); err != nil {
-		return nil, fmt.Errorf("chyba při vytváření projektu: %v", err)
+		return nil, i18n.Errorf(i18n.MsgErrProjectPost, err)
 	}
 
 	return &project, nil
 }
 
-func (c *Client) GetTasks(projectID string) ([]*Task, error) {
+func (c *Client) GetSections(ctx context.Context, projectID string) ([]*Section, error) {
+	req, err := c.createRequest(ctx, "GET", "/sections?project_id="+projectID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []*Section
+	if err := c.doRequest(req, &sections); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrSectionsGet, err)
+	}
+
+	return sections, nil
+}
+
+func (c *Client) CreateSection(ctx context.Context, projectID, name string) (*Section, error) {
+	payload := map[string]string{"project_id": projectID, "name": name}
+
+	req, err := c.createRequest(ctx, "POST", "/sections", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var section Section
+	if err := c.doRequest(req, &section); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrSectionPost, err)
+	}
+
+	return &section, nil
+}
+
+func (c *Client) GetTasks(ctx context.Context, projectID string) ([]*Task, error) {
 	url := "/tasks"
 	if projectID != "" {
 		url += "?project_id=" + projectID
 	}
 
-	req, err := c.createRequest("GET", url, nil)
+	req, err := c.createRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var tasks []*Task
 	if err := c.doRequest(req, &tasks); err != nil {
-		return nil, fmt.Errorf("chyba při získávání úkolů: %v", err)
+		return nil, i18n.Errorf(i18n.MsgErrTasksGet, err)
 	}
 
 	return tasks, nil
 }
 
-func (c *Client) CreateTask(task *CreateTaskRequest) (*Task, error) {
-	req, err := c.createRequest("POST", "/tasks", task)
+// GetCompletedTasks vrací úkoly projektu dokončené od okamžiku since.
+// REST endpoint /tasks vrací jen aktivní úkoly, proto se dokončené čtou
+// ze Sync API a stránkují přes offset.
+func (c *Client) GetCompletedTasks(ctx context.Context, projectID string, since time.Time) ([]*CompletedTask, error) {
+	var allTasks []*CompletedTask
+
+	for offset := 0; ; offset += completedPageSize {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(completedPageSize))
+		query.Set("offset", strconv.Itoa(offset))
+		if projectID != "" {
+			query.Set("project_id", projectID)
+		}
+		if !since.IsZero() {
+			query.Set("since", since.UTC().Format("2006-01-02T15:04:05"))
+		}
+
+		req, err := c.newRequest(ctx, "GET", syncBaseURL+"/completed/get_all?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page completedTasksResponse
+		if err := c.doRequest(req, &page); err != nil {
+			return nil, i18n.Errorf(i18n.MsgErrCompletedGet, err)
+		}
+
+		allTasks = append(allTasks, page.Items...)
+		if len(page.Items) < completedPageSize {
+			break
+		}
+	}
+
+	return allTasks, nil
+}
+
+func (c *Client) CreateTask(ctx context.Context, task *CreateTaskRequest) (*Task, error) {
+	req, err := c.createRequest(ctx, "POST", "/tasks", task)
 	if err != nil {
 		return nil, err
 	}
 
 	var createdTask Task
 	if err := c.doRequest(req, &createdTask); err != nil {
-		return nil, fmt.Errorf("chyba při vytváření úkolu: %v", err)
+		return nil, i18n.Errorf(i18n.MsgErrTaskCreate, err)
 	}
 
 	return &createdTask, nil
 }
 
-func (c *Client) UpdateTask(taskID string, updates map[string]interface{}) error {
-	req, err := c.createRequest("POST", "/tasks/"+taskID, updates)
+func (c *Client) UpdateTask(ctx context.Context, taskID string, updates map[string]interface{}) error {
+	req, err := c.createRequest(ctx, "POST", "/tasks/"+taskID, updates)
 	if err != nil {
 		return err
 	}
 
 	if err := c.doRequest(req, nil); err != nil {
-		return fmt.Errorf("chyba při aktualizaci úkolu: %v", err)
+		return i18n.Errorf(i18n.MsgErrTaskUpdate, err)
 	}
 
 	return nil
 }
 
-func (c *Client) CloseTask(taskID string) error {
-	req, err := c.createRequest("POST", "/tasks/"+taskID+"/close", nil)
+func (c *Client) CloseTask(ctx context.Context, taskID string) error {
+	req, err := c.createRequest(ctx, "POST", "/tasks/"+taskID+"/close", nil)
 	if err != nil {
 		return err
 	}
 
 	if err := c.doRequest(req, nil); err != nil {
-		return fmt.Errorf("chyba při uzavírání úkolu: %v", err)
+		return i18n.Errorf(i18n.MsgErrTaskClose, err)
 	}
 
 	return nil
 }
 
-func (c *Client) ReopenTask(taskID string) error {
-	req, err := c.createRequest("POST", "/tasks/"+taskID+"/reopen", nil)
+func (c *Client) ReopenTask(ctx context.Context, taskID string) error {
+	req, err := c.createRequest(ctx, "POST", "/tasks/"+taskID+"/reopen", nil)
 	if err != nil {
 		return err
 	}
 
 	if err := c.doRequest(req, nil); err != nil {
-		return fmt.Errorf("chyba při znovuotevření úkolu: %v", err)
+		return i18n.Errorf(i18n.MsgErrTaskReopen, err)
 	}
 
 	return nil
 }
 
-func (c *Client) FindTaskByDescription(projectID, description string) (*Task, error) {
-	tasks, err := c.GetTasks(projectID)
+func (c *Client) FindTaskByDescription(ctx context.Context, projectID, description string) (*Task, error) {
+	tasks, err := c.GetTasks(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -217,9 +364,11 @@ func (c *Client) FindTaskByDescription(projectID, description string) (*Task, er
 	return nil, nil
 }
 
-func (c *Client) createRequest(method, path string, body interface{}) (*http.Request, error) {
-	url := baseURL + path
+func (c *Client) createRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	return c.newRequest(ctx, method, baseURL+path, body)
+}
 
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Request, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -229,27 +378,74 @@ func (c *Client) createRequest(method, path string, body interface{}) (*http.Req
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
+	if method == http.MethodPost {
+		// Todoist podle X-Request-Id pozná opakovaný požadavek, takže
+		// i vytvoření úkolu lze po chybě bezpečně zopakovat.
+		req.Header.Set("X-Request-Id", newRequestID())
+	}
 
 	return req, nil
 }
 
+// doRequest odešle požadavek a při limitu nebo dočasné chybě ho podle
+// nastavení zopakuje. Retry-After od serveru má přednost před backoffem.
 func (c *Client) doRequest(req *http.Request, target interface{}) error {
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
+	canRetry := retryable(req)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if !canRetry || attempt > c.retry.MaxRetries || req.Context().Err() != nil {
+				return err
+			}
+			delay := c.retry.backoff(attempt)
+			logRetry(req, err.Error(), delay, attempt, c.retry.MaxRetries)
+			if req, err = c.retryRequest(req, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !retryableStatus(resp.StatusCode) || !canRetry || attempt > c.retry.MaxRetries {
+			return c.handleResponse(resp, target)
+		}
+
+		delay, ok := retryAfter(resp)
+		if !ok {
+			delay = c.retry.backoff(attempt)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		logRetry(req, fmt.Sprintf("HTTP %d", resp.StatusCode), delay, attempt, c.retry.MaxRetries)
+		if req, err = c.retryRequest(req, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) retryRequest(req *http.Request, delay time.Duration) (*http.Request, error) {
+	if err := wait(req, delay); err != nil {
+		return nil, err
 	}
+	return rewind(req)
+}
+
+func (c *Client) handleResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		// Tělo odpovědi může obsahovat cokoli, do chyby proto jde jen jeho
+		// začátek a bez přihlašovacích údajů.
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("API error %d: %s", resp.StatusCode, redact.String(strings.TrimSpace(string(body))))
 	}
 
 	if target != nil && resp.StatusCode != http.StatusNoContent {
@@ -259,23 +455,6 @@ func (c *Client) doRequest(req *http.Request, target interface{}) error {
 	return nil
 }
 
-func GetLabelPriority(labels []string) int {
-	priorityMap := map[string]int{
-		"urgent": 4,
-		"high":   3,
-		"medium": 2,
-		"low":    1,
-	}
-
-	for _, label := range labels {
-		if priority, exists := priorityMap[strings.ToLower(label)]; exists {
-			return priority
-		}
-	}
-
-	return 1 // Default priority
-}
-
-func FormatGitHubReference(issueNumber int, url string) string {
-	return fmt.Sprintf("GitHub Issue #%d: %s", issueNumber, url)
+func FormatGitHubReference(repo string, issueNumber int, url string) string {
+	return fmt.Sprintf("GitHub Issue %s#%d: %s", repo, issueNumber, url)
 }
@@ -0,0 +1,42 @@
+package todoist
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleResponseRedactsToken(t *testing.T) {
+	const token = "todoist-api-token-0123456789abcdef"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintf(w, `{"error": "invalid token %s", "request": {"authorization": "%s"}}`,
+			token, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	c := NewClient(token, RetryConfig{})
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.handleResponse(resp, nil)
+	if err == nil {
+		t.Fatal("handleResponse() = nil, want API error")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("handleResponse() error = %q, contains token", err)
+	}
+	if !strings.HasPrefix(err.Error(), "API error 401:") {
+		t.Errorf("handleResponse() error = %q, want API error 401", err)
+	}
+}
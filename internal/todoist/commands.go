@@ -0,0 +1,109 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github-todoist-sync/internal/i18n"
+)
+
+// MaxBatchCommands je nejvyšší počet příkazů v jednom požadavku Sync API.
+const MaxBatchCommands = 100
+
+// Command je zápis přes Sync API. UUID se vytváří jednou při sestavení
+// příkazu, takže opakované odeslání dávky Todoist rozpozná a neprovede
+// příkaz podruhé.
+type Command struct {
+	Type   string      `json:"type"`
+	UUID   string      `json:"uuid"`
+	TempID string      `json:"temp_id,omitempty"`
+	Args   interface{} `json:"args"`
+}
+
+func newCommand(commandType string, args interface{}) Command {
+	return Command{Type: commandType, UUID: newRequestID(), Args: args}
+}
+
+// AddItemCommand založí úkol. Na nový úkol se lze v další příkazech téže
+// dávky odkázat přes TempID místo ID.
+func AddItemCommand(task *CreateTaskRequest) Command {
+	command := newCommand("item_add", task)
+	command.TempID = newRequestID()
+	return command
+}
+
+func UpdateItemCommand(taskID string, updates map[string]interface{}) Command {
+	args := map[string]interface{}{"id": taskID}
+	for key, value := range updates {
+		args[key] = value
+	}
+	return newCommand("item_update", args)
+}
+
+func CloseItemCommand(taskID string) Command {
+	return newCommand("item_close", map[string]string{"id": taskID})
+}
+
+func UncompleteItemCommand(taskID string) Command {
+	return newCommand("item_uncomplete", map[string]string{"id": taskID})
+}
+
+// CommandResponse nese výsledek každého příkazu dávky podle jeho UUID.
+type CommandResponse struct {
+	SyncToken     string                     `json:"sync_token"`
+	SyncStatus    map[string]json.RawMessage `json:"sync_status"`
+	TempIDMapping map[string]string          `json:"temp_id_mapping"`
+}
+
+type commandError struct {
+	Code    int    `json:"error_code"`
+	Message string `json:"error"`
+}
+
+// Err vrací chybu příkazu s daným UUID, nil pokud uspěl.
+func (r *CommandResponse) Err(uuid string) error {
+	status, ok := r.SyncStatus[uuid]
+	if !ok {
+		return i18n.Errorf(i18n.MsgErrCommandNoResult, uuid)
+	}
+
+	var result string
+	if json.Unmarshal(status, &result) == nil && result == "ok" {
+		return nil
+	}
+
+	var cmdErr commandError
+	if err := json.Unmarshal(status, &cmdErr); err != nil {
+		return i18n.Errorf(i18n.MsgErrCommandStatus, status)
+	}
+	return fmt.Errorf("API error %d: %s", cmdErr.Code, cmdErr.Message)
+}
+
+// ExecuteCommands odešle dávku nejvýše MaxBatchCommands příkazů jedním
+// požadavkem.
+func (c *Client) ExecuteCommands(ctx context.Context, commands []Command) (*CommandResponse, error) {
+	if len(commands) > MaxBatchCommands {
+		return nil, i18n.Errorf(i18n.MsgErrBatchSize, len(commands), MaxBatchCommands)
+	}
+
+	encoded, err := json.Marshal(commands)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("commands", string(encoded))
+
+	req, err := c.newSyncRequest(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CommandResponse
+	if err := c.doRequest(req, &resp); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrBatchSend, err)
+	}
+
+	return &resp, nil
+}
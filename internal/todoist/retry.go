@@ -0,0 +1,123 @@
+package todoist
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+)
+
+// RetryConfig nastavuje opakování požadavků, které Todoist odmítl kvůli
+// limitu (429) nebo dočasné chybě serveru (5xx).
+type RetryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// backoff vrací čekání před pokusem attempt (od 1): exponenciálně rostoucí
+// strop omezený MaxDelay, ze kterého se náhodně volí horní polovina.
+func (r RetryConfig) backoff(attempt int) time.Duration {
+	delay := r.BaseDelay
+	for i := 1; i < attempt && delay < r.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	jitter, err := rand.Int(rand.Reader, big.NewInt(int64(half)+1))
+	if err != nil {
+		return delay
+	}
+	return half + time.Duration(jitter.Int64())
+}
+
+// retryable říká, zda lze požadavek bezpečně poslat znovu. POST požadavky
+// jsou bezpečné jen s X-Request-Id, podle kterého Todoist duplicity zahodí.
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("X-Request-Id") != "" && (req.Body == nil || req.GetBody != nil)
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter přečte hlavičku Retry-After v sekundách nebo jako HTTP datum.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// rewind připraví požadavek k opakovanému odeslání s novým tělem.
+func rewind(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return retry, nil
+}
+
+func wait(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+func logRetry(req *http.Request, reason string, delay time.Duration, attempt, max int) {
+	logging.Warn(i18n.MsgTodoistRetry, "method", req.Method, "path", req.URL.Path, "reason", reason,
+		"delay", delay.Round(time.Millisecond), "attempt", attempt, "max_attempts", max)
+}
+
+// newRequestID vytvoří náhodné UUID v4 pro hlavičku X-Request-Id.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,57 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github-todoist-sync/internal/i18n"
+)
+
+// FullSyncToken vyžádá od Sync API úplný stav místo změn.
+const FullSyncToken = "*"
+
+// SyncResponse je odpověď Sync API omezená na úkoly.
+type SyncResponse struct {
+	SyncToken string `json:"sync_token"`
+	FullSync  bool   `json:"full_sync"`
+	// Items obsahuje při úplné synchronizaci jen nedokončené úkoly, při
+	// přírůstkové všechny změněné včetně dokončených a smazaných.
+	Items []*Item `json:"items"`
+}
+
+// SyncItems načte úkoly změněné od syncToken. S FullSyncToken vrátí
+// všechny nedokončené úkoly. Token z odpovědi se předá dalšímu volání.
+func (c *Client) SyncItems(ctx context.Context, syncToken string) (*SyncResponse, error) {
+	form := url.Values{}
+	form.Set("sync_token", syncToken)
+	form.Set("resource_types", `["items"]`)
+
+	req, err := c.newSyncRequest(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SyncResponse
+	if err := c.doRequest(req, &resp); err != nil {
+		return nil, i18n.Errorf(i18n.MsgErrTasksSync, err)
+	}
+
+	return &resp, nil
+}
+
+// newSyncRequest sestaví požadavek na endpoint /sync. X-Request-Id povolí
+// jeho opakování, zápisy navíc chrání UUID jednotlivých příkazů.
+func (c *Client) newSyncRequest(ctx context.Context, form url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, syncBaseURL+"/sync", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Request-Id", newRequestID())
+
+	return req, nil
+}
@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github-todoist-sync/internal/daemon"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+)
+
+// GitHub posílá payloady o velikosti nejvýše 25 MB.
+const maxPayloadSize = 25 << 20
+
+type Queue interface {
+	Enqueue(job daemon.Job) bool
+}
+
+type githubPayload struct {
+	Action string `json:"action"`
+	Issue  *struct {
+		Number      int              `json:"number"`
+		PullRequest *json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+	Repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+// GitHubHandler přijímá webhooky issues, issue_comment a label a zařazuje
+// synchronizaci dotčeného issue, případně celého repozitáře, do fronty.
+type GitHubHandler struct {
+	secret []byte
+	queue  Queue
+}
+
+func NewGitHubHandler(secret string, queue Queue) *GitHubHandler {
+	return &GitHubHandler{
+		secret: []byte(secret),
+		queue:  queue,
+	}
+}
+
+func (h *GitHubHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.validSignature(r.Header.Get("X-Hub-Signature-256"), body) {
+		logging.Warn(i18n.MsgWebhookRejected, "source", "github")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	switch event {
+	case "ping":
+		w.WriteHeader(http.StatusOK)
+		return
+	case "issues", "issue_comment", "label":
+	default:
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var payload githubPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	job := daemon.Job{
+		Owner: payload.Repository.Owner.Login,
+		Repo:  payload.Repository.Name,
+	}
+
+	// Změna štítku se může týkat libovolného issue, proto se synchronizuje
+	// celý repozitář. Přejmenování ani smazání štítku neposune čas změny
+	// issues, musí se proto načíst všechna.
+	switch {
+	case event == "label":
+		job.Rescan = true
+	case payload.Issue == nil || payload.Issue.PullRequest != nil:
+		w.WriteHeader(http.StatusAccepted)
+		return
+	default:
+		job.Number = payload.Issue.Number
+	}
+
+	logging.Info(i18n.MsgWebhookGitHub, "webhook_event", event, "webhook_action", payload.Action, logging.Repo(job.Owner+"/"+job.Repo), "job", job.String())
+	if !h.queue.Enqueue(job) {
+		http.Error(w, "queue full", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *GitHubHandler) validSignature(header string, body []byte) bool {
+	signature, found := strings.CutPrefix(header, "sha256=")
+	if !found {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github-todoist-sync/internal/daemon"
+	"github-todoist-sync/internal/i18n"
+	"github-todoist-sync/internal/logging"
+	"github-todoist-sync/internal/todoist"
+)
+
+type todoistPayload struct {
+	EventName string        `json:"event_name"`
+	EventData *todoist.Item `json:"event_data"`
+}
+
+// TodoistHandler přijímá webhooky item:completed, item:uncompleted,
+// item:updated a item:deleted a zařazuje promítnutí změny úkolu do GitHubu
+// do fronty.
+type TodoistHandler struct {
+	secret []byte
+	queue  Queue
+}
+
+func NewTodoistHandler(clientSecret string, queue Queue) *TodoistHandler {
+	return &TodoistHandler{
+		secret: []byte(clientSecret),
+		queue:  queue,
+	}
+}
+
+func (h *TodoistHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.validSignature(r.Header.Get("X-Todoist-Hmac-SHA256"), body) {
+		logging.Warn(i18n.MsgWebhookRejected, "source", "todoist")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload todoistPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	switch payload.EventName {
+	case "item:completed", "item:uncompleted", "item:updated", "item:deleted":
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if payload.EventData == nil || payload.EventData.ID == "" {
+		http.Error(w, "missing event data", http.StatusBadRequest)
+		return
+	}
+
+	job := daemon.Job{
+		Task:        payload.EventData.Task(),
+		TaskDeleted: payload.EventName == "item:deleted" || payload.EventData.IsDeleted,
+	}
+
+	logging.Info(i18n.MsgWebhookTodoist, "webhook_event", payload.EventName, logging.TaskID(job.Task.ID))
+	if !h.queue.Enqueue(job) {
+		http.Error(w, "queue full", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Todoist opakuje doručení, pokud nedostane 200.
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *TodoistHandler) validSignature(header string, body []byte) bool {
+	expected, err := base64.StdEncoding.DecodeString(header)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}